@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// usedField is a field selected by the queries, typed from the bundled schema.
+type usedField struct {
+	Type      string
+	Field     string
+	FieldType string
+	// Arguments are the types of the arguments passed to the field, by name
+	Arguments map[string]string
+	// Position is where the field is first selected
+	Position *ast.Position
+	// Operations are the queries selecting the field
+	Operations []string
+}
+
+func (f *usedField) String() string {
+	return f.Type + "." + f.Field
+}
+
+// drift is a used field which has drifted in the live schema.
+type drift struct {
+	field   *usedField
+	problem string
+}
+
+func (d drift) String() string {
+	return fmt.Sprintf("%s:%d: %s %s (%s)", d.field.Position.Src.Name, d.field.Position.Line, d.field, d.problem, strings.Join(d.field.Operations, ", "))
+}
+
+// usedFields returns the fields selected by the queries, by type and field
+// name, validating the queries against the bundled schema.
+func usedFields(schemaPath string, queryPaths []string) (map[string]*usedField, error) {
+	source, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: schemaPath, Input: string(source)})
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	used := map[string]*usedField{}
+	for _, path := range queryPaths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		doc, errs := gqlparser.LoadQuery(schema, string(source))
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%s: %w", path, errs)
+		}
+		for _, op := range doc.Operations {
+			collectFields(used, path, op.Name, op.SelectionSet)
+		}
+		for _, fragment := range doc.Fragments {
+			collectFields(used, path, fragment.Name, fragment.SelectionSet)
+		}
+	}
+	return used, nil
+}
+
+func collectFields(used map[string]*usedField, path, operation string, selections ast.SelectionSet) {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Definition == nil || strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			key := s.ObjectDefinition.Name + "." + s.Name
+			f := used[key]
+			if f == nil {
+				position := *s.Position
+				position.Src = &ast.Source{Name: path}
+				f = &usedField{
+					Type:      s.ObjectDefinition.Name,
+					Field:     s.Name,
+					FieldType: s.Definition.Type.String(),
+					Arguments: map[string]string{},
+					Position:  &position,
+				}
+				used[key] = f
+			}
+			for _, arg := range s.Arguments {
+				if def := s.Definition.Arguments.ForName(arg.Name); def != nil {
+					f.Arguments[arg.Name] = def.Type.String()
+				}
+			}
+			if !slices.Contains(f.Operations, operation) {
+				f.Operations = append(f.Operations, operation)
+			}
+			collectFields(used, path, operation, s.SelectionSet)
+		case *ast.InlineFragment:
+			collectFields(used, path, operation, s.SelectionSet)
+		}
+	}
+}
+
+// compare returns the used fields which were removed, deprecated or changed
+// type in the live schema, along with the arguments passed to them.
+func compare(used map[string]*usedField, live schemaFields) []drift {
+	var drifts []drift
+	for _, f := range used {
+		fields, ok := live[f.Type]
+		if !ok {
+			drifts = append(drifts, drift{f, "is removed, with its type " + f.Type})
+			continue
+		}
+		info, ok := fields[f.Field]
+		if !ok {
+			drifts = append(drifts, drift{f, "is removed"})
+			continue
+		}
+		if info.Deprecated {
+			problem := "is deprecated"
+			if info.Reason != "" {
+				problem += ": " + strings.Join(strings.Fields(info.Reason), " ")
+			}
+			drifts = append(drifts, drift{f, problem})
+		}
+		if info.Type != f.FieldType {
+			drifts = append(drifts, drift{f, fmt.Sprintf("changed type from %s to %s", f.FieldType, info.Type)})
+		}
+		for name, argumentType := range f.Arguments {
+			liveType, ok := info.Arguments[name]
+			switch {
+			case !ok:
+				drifts = append(drifts, drift{f, "argument " + name + " is removed"})
+			case liveType != argumentType:
+				drifts = append(drifts, drift{f, fmt.Sprintf("argument %s changed type from %s to %s", name, argumentType, liveType)})
+			}
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		a, b := drifts[i].field.Position, drifts[j].field.Position
+		if a.Src.Name != b.Src.Name {
+			return a.Src.Name < b.Src.Name
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return drifts[i].problem < drifts[j].problem
+	})
+	return drifts
+}
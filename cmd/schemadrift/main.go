@@ -0,0 +1,84 @@
+// Command schemadrift reports the fields selected by the genqlient queries
+// which have drifted in the live Linear API since the bundled schema was
+// saved: fields and arguments which were removed, deprecated or changed type.
+//
+// The live schema is read from a saved introspection result, or a schema in
+// SDL, so the check runs offline:
+//
+//	go run ./cmd/schemadrift -live introspection.json
+//
+// or introspected from an endpoint, optionally saving the result for later
+// runs:
+//
+//	LINEAR_TOKEN=lin_api_... go run ./cmd/schemadrift -endpoint https://api.linear.app/graphql -save introspection.json
+//
+// It exits with status 1 when drift is found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultEndpoint = "https://api.linear.app/graphql"
+
+func main() {
+	schemaPath := flag.String("schema", "gql/schema.graphql", "the bundled GraphQL schema")
+	queries := flag.String("queries", "gql/genqlient.graphql,gql/tables.graphql", "comma separated genqlient query files")
+	livePath := flag.String("live", "", "a saved introspection result (.json) or schema (.graphql) of the live API")
+	endpoint := flag.String("endpoint", "", "introspect the live API at this endpoint, such as "+defaultEndpoint)
+	token := flag.String("token", os.Getenv("LINEAR_TOKEN"), "the API key, or \"Bearer <token>\" for an OAuth token, to introspect the endpoint with")
+	savePath := flag.String("save", "", "save the introspection result of the endpoint to this file")
+	flag.Parse()
+
+	if (*livePath == "") == (*endpoint == "") {
+		fmt.Fprintln(os.Stderr, "schemadrift: one of -live or -endpoint is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	drifts, err := run(*schemaPath, strings.Split(*queries, ","), *livePath, *endpoint, *token, *savePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "schemadrift:", err)
+		os.Exit(2)
+	}
+	for _, d := range drifts {
+		fmt.Println(d)
+	}
+	if len(drifts) > 0 {
+		fmt.Fprintf(os.Stderr, "schemadrift: %d fields have drifted\n", len(drifts))
+		os.Exit(1)
+	}
+}
+
+func run(schemaPath string, queryPaths []string, livePath, endpoint, token, savePath string) ([]drift, error) {
+	used, err := usedFields(schemaPath, queryPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var live schemaFields
+	if endpoint != "" {
+		result, err := introspect(endpoint, token)
+		if err != nil {
+			return nil, err
+		}
+		if savePath != "" {
+			if err := os.WriteFile(savePath, result, 0644); err != nil {
+				return nil, err
+			}
+		}
+		live, err = introspectionFields(result)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		live, err = readSchemaFields(livePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return compare(used, live), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var wantDrifts = []string{
+	"testdata/queries.graphql:2: Query.teams argument filter is removed (listTeams)",
+	"testdata/queries.graphql:6: Team.cycleCalenderUrl is deprecated: Use cycleCalendarUrl instead. (listTeams, getTeam)",
+	"testdata/queries.graphql:7: Team.cycleDuration changed type from Float! to Int! (listTeams)",
+	"testdata/queries.graphql:8: Team.organization is removed (listTeams)",
+	"testdata/queries.graphql:9: Organization.id is removed, with its type Organization (listTeams)",
+	"testdata/queries.graphql:10: Organization.name is removed, with its type Organization (listTeams)",
+}
+
+func driftStrings(drifts []drift) []string {
+	var lines []string
+	for _, d := range drifts {
+		lines = append(lines, d.String())
+	}
+	return lines
+}
+
+func TestDriftFromSavedIntrospection(t *testing.T) {
+	drifts, err := run("testdata/schema.graphql", []string{"testdata/queries.graphql"}, "testdata/introspection.json", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := driftStrings(drifts); !reflect.DeepEqual(got, wantDrifts) {
+		t.Errorf("got drifts\n%q\nwant\n%q", got, wantDrifts)
+	}
+}
+
+func TestNoDriftAgainstBundledSchema(t *testing.T) {
+	drifts, err := run("testdata/schema.graphql", []string{"testdata/queries.graphql"}, "testdata/schema.graphql", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) > 0 {
+		t.Errorf("got drifts %q, want none", driftStrings(drifts))
+	}
+}
+
+func TestDriftFromEndpoint(t *testing.T) {
+	introspection, err := os.ReadFile("testdata/introspection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "lin_api_test" {
+			t.Errorf("got Authorization %q, want the API key", got)
+		}
+		w.Write(introspection)
+	}))
+	defer server.Close()
+
+	savePath := filepath.Join(t.TempDir(), "introspection.json")
+	drifts, err := run("testdata/schema.graphql", []string{"testdata/queries.graphql"}, "", server.URL, "lin_api_test", savePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := driftStrings(drifts); !reflect.DeepEqual(got, wantDrifts) {
+		t.Errorf("got drifts\n%q\nwant\n%q", got, wantDrifts)
+	}
+	if saved, err := os.ReadFile(savePath); err != nil || string(saved) != string(introspection) {
+		t.Errorf("the introspection result was not saved: %v", err)
+	}
+}
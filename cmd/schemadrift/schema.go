@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// schemaFields are the fields of the types of a schema, by type and field
+// name.
+type schemaFields map[string]map[string]*fieldInfo
+
+// fieldInfo describes a field of a schema.
+type fieldInfo struct {
+	// Type is the type of the field, such as "[String!]!"
+	Type       string
+	Deprecated bool
+	Reason     string
+	// Arguments are the types of the arguments of the field, by name
+	Arguments map[string]string
+}
+
+// introspectionQuery reads the fields of every type, deprecated ones included.
+const introspectionQuery = `query schemadrift {
+  __schema {
+    types {
+      name
+      fields(includeDeprecated: true) {
+        name
+        isDeprecated
+        deprecationReason
+        args {
+          name
+          type { ...TypeRef }
+        }
+        type { ...TypeRef }
+      }
+      inputFields {
+        name
+        type { ...TypeRef }
+      }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+        }
+      }
+    }
+  }
+}`
+
+// introspect runs the introspection query against the endpoint and returns
+// its result.
+func introspect(endpoint, token string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"query": introspectionQuery})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	result, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspecting %s: %s: %s", endpoint, resp.Status, result)
+	}
+	return result, nil
+}
+
+// readSchemaFields reads the fields of a saved introspection result, or of a
+// schema in SDL.
+func readSchemaFields(path string) (schemaFields, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".json" {
+		return introspectionFields(source)
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: path, Input: string(source)})
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+	return sdlFields(schema), nil
+}
+
+// sdlFields returns the fields of a parsed schema.
+func sdlFields(schema *ast.Schema) schemaFields {
+	fields := schemaFields{}
+	for name, def := range schema.Types {
+		if len(def.Fields) == 0 {
+			continue
+		}
+		fields[name] = map[string]*fieldInfo{}
+		for _, field := range def.Fields {
+			info := &fieldInfo{Type: field.Type.String(), Arguments: map[string]string{}}
+			if deprecated := field.Directives.ForName("deprecated"); deprecated != nil {
+				info.Deprecated = true
+				if reason := deprecated.Arguments.ForName("reason"); reason != nil {
+					info.Reason = reason.Value.Raw
+				}
+			}
+			for _, arg := range field.Arguments {
+				info.Arguments[arg.Name] = arg.Type.String()
+			}
+			fields[name][field.Name] = info
+		}
+	}
+	return fields
+}
+
+type introspectionResult struct {
+	Data *struct {
+		Schema *introspectionSchema `json:"__schema"`
+	} `json:"data"`
+	Schema *introspectionSchema `json:"__schema"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type introspectionSchema struct {
+	Types []struct {
+		Name        string               `json:"name"`
+		Fields      []introspectionField `json:"fields"`
+		InputFields []introspectionField `json:"inputFields"`
+	} `json:"types"`
+}
+
+type introspectionField struct {
+	Name              string `json:"name"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+	Args              []struct {
+		Name string   `json:"name"`
+		Type *typeRef `json:"type"`
+	} `json:"args"`
+	Type *typeRef `json:"type"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+// String returns the type in SDL, such as "[String!]!".
+func (t *typeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// introspectionFields returns the fields of an introspection result, either
+// the response of the endpoint or its data.
+func introspectionFields(source []byte) (schemaFields, error) {
+	var result introspectionResult
+	if err := json.Unmarshal(source, &result); err != nil {
+		return nil, fmt.Errorf("reading the introspection result: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		var messages []string
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, errors.New("introspection failed: " + strings.Join(messages, "; "))
+	}
+	schema := result.Schema
+	if result.Data != nil {
+		schema = result.Data.Schema
+	}
+	if schema == nil {
+		return nil, errors.New("the introspection result has no __schema")
+	}
+
+	fields := schemaFields{}
+	for _, t := range schema.Types {
+		for _, field := range append(t.Fields, t.InputFields...) {
+			if fields[t.Name] == nil {
+				fields[t.Name] = map[string]*fieldInfo{}
+			}
+			info := &fieldInfo{
+				Type:       field.Type.String(),
+				Deprecated: field.IsDeprecated,
+				Reason:     field.DeprecationReason,
+				Arguments:  map[string]string{},
+			}
+			for _, arg := range field.Args {
+				info.Arguments[arg.Name] = arg.Type.String()
+			}
+			fields[t.Name][field.Name] = info
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,189 @@
+// Command tablegen generates Linear tables from the GraphQL schema and a spec
+// per table.
+//
+// Each spec in the specs directory is an HCL file with a table block naming
+// the schema type of the table, its list and get query fields, the scalar
+// fields read into columns and the related entities kept as JSON columns:
+//
+//	table "linear_attachment" {
+//	  description = "Linear Attachment"
+//	  type        = "Attachment"
+//	  list        = "attachments"
+//	  get         = "attachment"
+//	  title       = "title"
+//	  fields      = ["createdAt", "source", "subtitle", "url"]
+//
+//	  relation "creator" {
+//	    fields = ["id", "email", "name"]
+//	  }
+//	}
+//
+// A parent block names a relation whose entity lists the entities of the
+// table in a field named like the list query field, such as the attachments
+// of an issue: queries with an equals qual on the _id column of the relation
+// page through that list instead.
+//
+// For every table, tablegen writes its genqlient queries to the queries file,
+// the exported wrappers of the queries to the wrappers file, and a
+// table_<name>.go file to the tables directory with the columns, described
+// and typed from the schema, the list and get functions, and the key columns
+// pushed down to the filter of the list query.
+//
+// It is run by go generate in the gql package, before genqlient.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "schema.graphql", "the GraphQL schema")
+	specsDir := flag.String("specs", "tables", "the directory of the table specs")
+	queriesPath := flag.String("queries", "tables.graphql", "the genqlient queries to write")
+	wrappersPath := flag.String("wrappers", "tables.go", "the query wrappers to write")
+	tablesDir := flag.String("tables", "../linear", "the directory of the table files to write")
+	flag.Parse()
+
+	if err := run(*schemaPath, *specsDir, *queriesPath, *wrappersPath, *tablesDir); err != nil {
+		fmt.Fprintln(os.Stderr, "tablegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(schemaPath, specsDir, queriesPath, wrappersPath, tablesDir string) error {
+	schema, err := loadSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+
+	specs, err := readSpecs(specsDir)
+	if err != nil {
+		return err
+	}
+
+	var tables []*table
+	for _, spec := range specs {
+		t, err := newTable(schema, spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", spec.Name, err)
+		}
+		tables = append(tables, t)
+	}
+
+	var queries, wrappers bytes.Buffer
+	if err := queriesTemplate.Execute(&queries, tables); err != nil {
+		return err
+	}
+	if err := wrappersTemplate.Execute(&wrappers, tables); err != nil {
+		return err
+	}
+	if err := writeGo(wrappersPath, wrappers.Bytes()); err != nil {
+		return err
+	}
+	if err := os.WriteFile(queriesPath, queries.Bytes(), 0644); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		var source bytes.Buffer
+		if err := tableTemplate.Execute(&source, t); err != nil {
+			return err
+		}
+		if err := writeGo(filepath.Join(tablesDir, "table_"+t.Name+".go"), source.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadSchema(path string) (*ast.Schema, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: path, Input: string(source)})
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+	return schema, nil
+}
+
+// specFile is a file of table specs.
+type specFile struct {
+	Tables []*tableSpec `hcl:"table,block"`
+}
+
+// tableSpec describes a table generated from a schema type.
+type tableSpec struct {
+	Name        string          `hcl:"name,label"`
+	Description string          `hcl:"description"`
+	Type        string          `hcl:"type"`
+	List        string          `hcl:"list"`
+	Get         string          `hcl:"get"`
+	Title       string          `hcl:"title"`
+	Fields      []string        `hcl:"fields"`
+	Relations   []*relationSpec `hcl:"relation,block"`
+	Parents     []*parentSpec   `hcl:"parent,block"`
+}
+
+// relationSpec is a related entity of a table, kept as a JSON column with the
+// identifier of the entity in an _id column.
+type relationSpec struct {
+	Field  string   `hcl:"field,label"`
+	Fields []string `hcl:"fields"`
+}
+
+// parentSpec is a related entity of a table with its own list of the
+// entities of the table, walked when a query has an _id qual on it.
+type parentSpec struct {
+	Field string `hcl:"field,label"`
+}
+
+func readSpecs(dir string) ([]*tableSpec, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.hcl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	parser := hclparse.NewParser()
+	var specs []*tableSpec
+	for _, path := range paths {
+		file, diags := parser.ParseHCLFile(path)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		var f specFile
+		if diags := gohcl.DecodeBody(file.Body, nil, &f); diags.HasErrors() {
+			return nil, diags
+		}
+		specs = append(specs, f.Tables...)
+	}
+	return specs, nil
+}
+
+func writeGo(path string, source []byte) error {
+	formatted, err := formatSource(source)
+	if err != nil {
+		return fmt.Errorf("formatting %s: %w", path, err)
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
+
+// lowerFirst lowers the first letter of a description, to embed it in another.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedFilesUpToDate regenerates the tables from the specs and checks
+// that the generated files in the tree match.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	specs, err := readSpecs("../../gql/tables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) == 0 {
+		t.Fatal("no table specs")
+	}
+	if err := run("../../gql/schema.graphql", "../../gql/tables", filepath.Join(dir, "tables.graphql"), filepath.Join(dir, "tables.go"), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"tables.graphql": "../../gql/tables.graphql",
+		"tables.go":      "../../gql/tables.go",
+	}
+	for _, spec := range specs {
+		files["table_"+spec.Name+".go"] = "../../linear/table_" + spec.Name + ".go"
+	}
+	for generated, path := range files {
+		want, err := os.ReadFile(filepath.Join(dir, generated))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s is out of date, run go generate ./gql", path)
+		}
+	}
+}
+
+func TestNewTableRejectsUnknownFields(t *testing.T) {
+	specs := []*tableSpec{
+		{Name: "linear_attachment", Type: "Attachment", List: "attachments", Get: "attachment", Title: "title", Fields: []string{"cycleCalenderUrl"}},
+		{Name: "linear_attachment", Type: "Attachment", List: "attachments", Get: "attachment", Title: "title", Fields: []string{"issue"}},
+		{Name: "linear_attachment", Type: "Attachment", List: "teams", Get: "attachment", Title: "title"},
+		{Name: "linear_attachment", Type: "Attachment", List: "attachments", Get: "attachment", Title: "title", Relations: []*relationSpec{{Field: "issue", Fields: []string{"children"}}}},
+		{Name: "linear_attachment", Type: "Attachment", List: "attachments", Get: "attachment", Title: "title", Fields: []string{"title"}},
+		{Name: "linear_attachment", Type: "Attachment", List: "attachments", Get: "attachment", Title: "title", Parents: []*parentSpec{{Field: "issue"}}},
+		{Name: "linear_attachment", Type: "Attachment", List: "attachments", Get: "attachment", Title: "title", Relations: []*relationSpec{{Field: "creator", Fields: []string{"id"}}}, Parents: []*parentSpec{{Field: "creator"}}},
+	}
+
+	schema, err := loadSchema("../../gql/schema.graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range specs {
+		if _, err := newTable(schema, spec); err == nil {
+			t.Errorf("got no error for spec %+v", spec)
+		}
+	}
+}
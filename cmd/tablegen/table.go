@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// table is a table to generate, resolved against the schema.
+type table struct {
+	Name        string
+	Description string
+	// Entity and Entities name the list and get functions, such as Attachment
+	// and Attachments
+	Entity   string
+	Entities string
+	// List and Get are the query fields of the list and get queries
+	List string
+	Get  string
+	// Filter is the input type of the filter of the list query
+	Filter string
+	// Title is the field of the title column, and TitleField its Go field
+	Title      string
+	TitleField string
+	// GetField is the Go field of the entity in the get response
+	GetField  string
+	Columns   []*column
+	Relations []*relation
+	// Filters are the key columns pushed down to the filter, by Go field path
+	Filters []*filter
+	Parents []*parent
+}
+
+// column is a column of a table read from a field of its type.
+type column struct {
+	Name        string
+	Field       string
+	Description string
+	Type        string
+	// Transform reads the column from a field of a related entity
+	Transform string
+	// Relation is set on the columns of related entities, selected by the
+	// relations of the table
+	Relation bool
+	// Selects is set when the column isn't read from the field named after it
+	Selects bool
+}
+
+// relation is a related entity selected by the queries of a table.
+type relation struct {
+	Field  string
+	Fields []string
+}
+
+// parent is a related entity listing the entities of a table, paged through
+// when a query has an equals qual on its key column.
+type parent struct {
+	// Field is the query field getting the parent by id, and Name its Go
+	// name
+	Field string
+	Name  string
+	// Column is the _id column of the parent
+	Column string
+}
+
+// filter is a key column pushed down to the comparator at the path of the
+// filter.
+type filter struct {
+	Column string
+	Path   string
+}
+
+// scalarColumnTypes are the column types of the schema scalars.
+var scalarColumnTypes = map[string]string{
+	"ID":           "proto.ColumnType_STRING",
+	"String":       "proto.ColumnType_STRING",
+	"Int":          "proto.ColumnType_INT",
+	"Float":        "proto.ColumnType_DOUBLE",
+	"Boolean":      "proto.ColumnType_BOOL",
+	"DateTime":     "proto.ColumnType_TIMESTAMP",
+	"TimelessDate": "proto.ColumnType_TIMESTAMP",
+	"JSON":         "proto.ColumnType_JSON",
+	"JSONObject":   "proto.ColumnType_JSON",
+}
+
+func newTable(schema *ast.Schema, spec *tableSpec) (*table, error) {
+	typ := schema.Types[spec.Type]
+	if typ == nil || typ.Kind != ast.Object {
+		return nil, fmt.Errorf("schema has no type %s", spec.Type)
+	}
+	listField := schema.Query.Fields.ForName(spec.List)
+	if listField == nil || listField.Type.Name() != spec.Type+"Connection" {
+		return nil, fmt.Errorf("query %s does not list %s", spec.List, spec.Type)
+	}
+	getField := schema.Query.Fields.ForName(spec.Get)
+	if getField == nil || getField.Type.Name() != spec.Type || getField.Arguments.ForName("id") == nil {
+		return nil, fmt.Errorf("query %s does not get %s by id", spec.Get, spec.Type)
+	}
+	filterArgument := listField.Arguments.ForName("filter")
+	if filterArgument == nil {
+		return nil, fmt.Errorf("query %s has no filter", spec.List)
+	}
+	filterType := schema.Types[filterArgument.Type.Name()]
+
+	t := &table{
+		Name:        spec.Name,
+		Description: spec.Description,
+		Entity:      spec.Type,
+		Entities:    strcase.ToCamel(spec.List),
+		List:        spec.List,
+		Get:         spec.Get,
+		Filter:      filterType.Name,
+		Title:       spec.Title,
+		TitleField:  strcase.ToCamel(spec.Title),
+		GetField:    strcase.ToCamel(spec.Get),
+	}
+
+	fields := append([]string{"id"}, spec.Fields...)
+	slices.Sort(fields[1:])
+	for _, name := range slices.Compact(fields) {
+		field := typ.Fields.ForName(name)
+		if field == nil {
+			return nil, fmt.Errorf("type %s has no field %s", spec.Type, name)
+		}
+		if name == "title" {
+			return nil, errors.New(`the title field is read into the title column with title = "title"`)
+		}
+		columnType, err := fieldColumnType(schema, field)
+		if err != nil {
+			return nil, err
+		}
+		t.Columns = append(t.Columns, &column{
+			Name:        strcase.ToSnake(name),
+			Field:       name,
+			Description: description(field),
+			Type:        columnType,
+			Selects:     strcase.ToLowerCamel(strcase.ToSnake(name)) != name,
+		})
+		if name != "id" && comparator(schema, filterType, name) {
+			t.Filters = append(t.Filters, &filter{Column: strcase.ToSnake(name), Path: strcase.ToCamel(name)})
+		}
+	}
+	titleField := typ.Fields.ForName(spec.Title)
+	if titleField == nil {
+		return nil, fmt.Errorf("type %s has no title field %s", spec.Type, spec.Title)
+	}
+	if _, err := fieldColumnType(schema, titleField); err != nil {
+		return nil, err
+	}
+	if spec.Title == "title" && comparator(schema, filterType, "title") {
+		t.Filters = append(t.Filters, &filter{Column: "title", Path: "Title"})
+	}
+
+	for _, rs := range spec.Relations {
+		field := typ.Fields.ForName(rs.Field)
+		if field == nil || schema.Types[field.Type.Name()].Kind != ast.Object || field.Type.Elem != nil {
+			return nil, fmt.Errorf("type %s has no related entity %s", spec.Type, rs.Field)
+		}
+		related := schema.Types[field.Type.Name()]
+		for _, name := range rs.Fields {
+			relatedField := related.Fields.ForName(name)
+			if relatedField == nil {
+				return nil, fmt.Errorf("type %s has no field %s", related.Name, name)
+			}
+			if _, err := fieldColumnType(schema, relatedField); err != nil {
+				return nil, err
+			}
+		}
+		t.Relations = append(t.Relations, &relation{Field: rs.Field, Fields: rs.Fields})
+		t.Columns = append(t.Columns, &column{
+			Name:        strcase.ToSnake(rs.Field),
+			Field:       rs.Field,
+			Description: description(field),
+			Type:        "proto.ColumnType_JSON",
+			Relation:    true,
+			Selects:     strcase.ToLowerCamel(strcase.ToSnake(rs.Field)) != rs.Field,
+		})
+		if !slices.Contains(rs.Fields, "id") {
+			continue
+		}
+		idColumn := strcase.ToSnake(rs.Field) + "_id"
+		t.Columns = append(t.Columns, &column{
+			Name:        idColumn,
+			Field:       rs.Field,
+			Description: "The unique identifier of " + lowerFirst(description(field)),
+			Type:        "proto.ColumnType_STRING",
+			Transform:   strcase.ToCamel(rs.Field) + ".Id",
+			Relation:    true,
+			Selects:     true,
+		})
+		if relatedFilter := filterType.Fields.ForName(rs.Field); relatedFilter != nil {
+			if comparator(schema, schema.Types[relatedFilter.Type.Name()], "id") {
+				t.Filters = append(t.Filters, &filter{Column: idColumn, Path: strcase.ToCamel(rs.Field) + ".Id"})
+			}
+		}
+	}
+
+	for _, ps := range spec.Parents {
+		if !slices.ContainsFunc(spec.Relations, func(rs *relationSpec) bool {
+			return rs.Field == ps.Field && slices.Contains(rs.Fields, "id")
+		}) {
+			return nil, fmt.Errorf("parent %s is not a relation selecting its id", ps.Field)
+		}
+		parentField := schema.Query.Fields.ForName(ps.Field)
+		if parentField == nil || parentField.Arguments.ForName("id") == nil {
+			return nil, fmt.Errorf("query %s does not get the parent %s by id", ps.Field, ps.Field)
+		}
+		parentList := schema.Types[parentField.Type.Name()].Fields.ForName(spec.List)
+		if parentList == nil || parentList.Type.Name() != listField.Type.Name() || parentList.Arguments.ForName("filter") == nil ||
+			parentList.Arguments.ForName("filter").Type.Name() != filterType.Name {
+			return nil, fmt.Errorf("type %s does not list %s with a %s", parentField.Type.Name(), spec.List, filterType.Name)
+		}
+		t.Parents = append(t.Parents, &parent{
+			Field:  ps.Field,
+			Name:   strcase.ToCamel(ps.Field),
+			Column: strcase.ToSnake(ps.Field) + "_id",
+		})
+	}
+	return t, nil
+}
+
+// Selection returns the selection set of the entity in the queries, indented
+// by the number of spaces.
+func (t *table) Selection(indent int) string {
+	prefix := strings.Repeat(" ", indent)
+	var lines []string
+	titleSelected := false
+	for _, c := range t.Columns {
+		if !c.Relation {
+			lines = append(lines, prefix+c.Field)
+			titleSelected = titleSelected || c.Field == t.Title
+		}
+	}
+	// the title column may read a field without a column of its own
+	if !titleSelected {
+		lines = append(lines, prefix+t.Title)
+	}
+	for _, r := range t.Relations {
+		lines = append(lines, prefix+"# @genqlient(pointer: true)", prefix+r.Field+" {")
+		for _, field := range r.Fields {
+			lines = append(lines, prefix+"  "+field)
+		}
+		lines = append(lines, prefix+"}")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fieldColumnType returns the column type of a field without arguments,
+// whose value is a scalar, an enum or a list of them.
+func fieldColumnType(schema *ast.Schema, field *ast.FieldDefinition) (string, error) {
+	if len(field.Arguments) > 0 {
+		return "", fmt.Errorf("field %s has arguments", field.Name)
+	}
+	named := schema.Types[field.Type.Name()]
+	switch {
+	case named.Kind == ast.Enum:
+		if field.Type.Elem != nil {
+			return "proto.ColumnType_JSON", nil
+		}
+		return "proto.ColumnType_STRING", nil
+	case named.Kind != ast.Scalar:
+		return "", fmt.Errorf("field %s is not a scalar, it can only be selected by a relation", field.Name)
+	case field.Type.Elem != nil:
+		return "proto.ColumnType_JSON", nil
+	}
+	columnType, ok := scalarColumnTypes[named.Name]
+	if !ok {
+		return "", fmt.Errorf("field %s has an unknown scalar type %s", field.Name, named.Name)
+	}
+	return columnType, nil
+}
+
+// comparator reports whether the filter compares the field.
+func comparator(schema *ast.Schema, filterType *ast.Definition, name string) bool {
+	if filterType == nil {
+		return false
+	}
+	field := filterType.Fields.ForName(name)
+	return field != nil && field.Type.Elem == nil && strings.HasSuffix(field.Type.Name(), "Comparator")
+}
+
+// description returns the description of a field on a single line.
+func description(field *ast.FieldDefinition) string {
+	return strings.Join(strings.Fields(field.Description), " ")
+}
+
+func formatSource(source []byte) ([]byte, error) {
+	formatted, err := format.Source(source)
+	if err != nil {
+		return nil, errors.Join(err, errors.New(string(source)))
+	}
+	return formatted, nil
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+)
+
+var funcs = template.FuncMap{
+	"lowerFirst": lowerFirst,
+	"words":      func(s string) string { return strcase.ToDelimited(s, ' ') },
+}
+
+// queriesTemplate writes the genqlient list and get queries of the tables.
+var queriesTemplate = template.Must(template.New("queries").Funcs(funcs).Parse(`# Code generated by tablegen. DO NOT EDIT.
+{{- range $table := .}}
+
+# @genqlient(omitempty: true,pointer: true)
+query list{{.Entities}}(
+  # @genqlient(pointer: false)
+  $first: Int
+  # @genqlient(pointer: false)
+  $after: String
+  # @genqlient(pointer: false)
+  $includeArchived: Boolean
+  $filter: {{.Filter}}
+) {
+  {{.List}}(
+    first: $first
+    after: $after
+    filter: $filter
+    includeArchived: $includeArchived
+  ) {
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
+    nodes {
+{{.Selection 6}}
+    }
+  }
+}
+
+{{- range $parent := .Parents}}{{with $table}}
+
+# @genqlient(omitempty: true,pointer: true)
+query list{{$parent.Name}}{{.Entities}}(
+  ${{lowerFirst $parent.Name}}Id: String!
+  # @genqlient(pointer: false)
+  $first: Int
+  # @genqlient(pointer: false)
+  $after: String
+  # @genqlient(pointer: false)
+  $includeArchived: Boolean
+  $filter: {{.Filter}}
+) {
+  {{$parent.Field}}(id: ${{lowerFirst $parent.Name}}Id) {
+    {{.List}}(
+      first: $first
+      after: $after
+      filter: $filter
+      includeArchived: $includeArchived
+    ) {
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+      nodes {
+{{.Selection 8}}
+      }
+    }
+  }
+}
+{{- end}}{{end}}
+
+# @genqlient(pointer: true)
+query get{{.Entity}}(${{lowerFirst .Entity}}Id: String!) {
+  {{.Get}}(id: ${{lowerFirst .Entity}}Id) {
+{{.Selection 4}}
+  }
+}
+{{- end}}
+`))
+
+// wrappersTemplate writes the exported wrappers of the queries.
+var wrappersTemplate = template.Must(template.New("wrappers").Funcs(funcs).Parse(`// Code generated by tablegen. DO NOT EDIT.
+
+package gql
+
+import (
+	"context"
+
+	"github.com/Khan/genqlient/graphql"
+)
+{{range $table := .}}
+func List{{.Entities}}(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *{{.Filter}}) (*list{{.Entities}}Response, error) {
+	return list{{.Entities}}(ctx, client, first, after, includeArchived, filter)
+}
+{{range $parent := .Parents}}{{with $table}}
+func List{{$parent.Name}}{{.Entities}}(ctx context.Context, client graphql.Client, {{lowerFirst $parent.Name}}Id *string, first int, after string, includeArchived bool, filter *{{.Filter}}) (*list{{$parent.Name}}{{.Entities}}Response, error) {
+	return list{{$parent.Name}}{{.Entities}}(ctx, client, {{lowerFirst $parent.Name}}Id, first, after, includeArchived, filter)
+}
+{{end}}{{end}}
+func Get{{.Entity}}(ctx context.Context, client graphql.Client, id *string) (*get{{.Entity}}Response, error) {
+	return get{{.Entity}}(ctx, client, id)
+}
+{{end}}`))
+
+// tableTemplate writes the table definition of a table.
+var tableTemplate = template.Must(template.New("table").Funcs(funcs).Parse(`// Code generated by tablegen. DO NOT EDIT.
+
+package linear
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-linear/gql"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableLinear{{.Entity}}(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "{{.Name}}",
+		Description:       "{{.Description}}",
+		GetMatrixItemFunc: workspaceMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: list{{.Entities}},
+{{- if .Parents}}
+			KeyColumns: append(filterKeyColumns[gql.{{.Filter}}]({{lowerFirst .Entity}}QualFilters),
+{{- range .Parents}}
+				&plugin.KeyColumn{
+					Name:    "{{.Column}}",
+					Require: plugin.Optional,
+				},
+{{- end}}
+			),
+{{- else}}
+			KeyColumns: filterKeyColumns[gql.{{.Filter}}]({{lowerFirst .Entity}}QualFilters),
+{{- end}}
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("id"),
+			Hydrate:    get{{.Entity}},
+		},
+		Columns: commonColumns([]*plugin.Column{
+{{- range .Columns}}
+			{
+				Name:        "{{.Name}}",
+				Description: {{printf "%q" .Description}},
+				Type:        {{.Type}},
+{{- if .Transform}}
+				Transform:   transform.FromField("{{.Transform}}"),
+{{- end}}
+			},
+{{- end}}
+
+			// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The {{words .Entity}}'s title.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("{{.TitleField}}"),
+			},
+		}),
+	}
+}
+
+// {{lowerFirst .Entity}}ColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var {{lowerFirst .Entity}}ColumnFields = columnFields{
+{{- range .Columns}}{{if .Selects}}
+	"{{.Name}}": {"{{.Field}}"},
+{{- end}}{{end}}
+	"title": {"{{.Title}}"},
+}
+
+// LIST FUNCTION
+
+func list{{.Entities}}(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("{{.Name}}.list{{.Entities}}", "connection_error", err)
+		return nil, err
+	}
+
+	var endCursor string
+
+	// set page size
+	var pageSize int = int(conn.pageSize)
+	if d.QueryContext.Limit != nil {
+		if int(*d.QueryContext.Limit) < pageSize {
+			pageSize = int(*d.QueryContext.Limit)
+		}
+	}
+
+	// set the requested filters
+	filters := set{{.Entity}}Filters(d, ctx)
+{{- range .Parents}}
+
+	// walk the {{words .Name}}'s {{words $.Entities}} directly, if the {{words .Name}} is known
+	if d.EqualsQualString("{{.Column}}") != "" {
+		return list{{$.Entities}}By{{.Name}}(ctx, d, conn, pageSize, &filters)
+	}
+{{- end}}
+
+	for {
+		list{{.Entity}}Response, err := gql.List{{.Entities}}(ctx, conn.selectColumns(d, {{lowerFirst .Entity}}ColumnFields), pageSize, endCursor, true, &filters)
+		if err != nil {
+			plugin.Logger(ctx).Error("{{.Name}}.list{{.Entities}}", "api_error", err)
+			return nil, err
+		}
+
+		for _, node := range list{{.Entity}}Response.{{.Entities}}.Nodes {
+			d.StreamListItem(ctx, node)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*list{{.Entity}}Response.{{.Entities}}.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *list{{.Entity}}Response.{{.Entities}}.PageInfo.EndCursor
+	}
+
+	return nil, nil
+}
+
+{{range $parent := .Parents}}{{with $}}func list{{.Entities}}By{{$parent.Name}}(ctx context.Context, d *plugin.QueryData, conn *linearClient, pageSize int, filters *gql.{{.Filter}}) (interface{}, error) {
+	{{lowerFirst $parent.Name}}Id := d.EqualsQualString("{{$parent.Column}}")
+	var endCursor string
+
+	for {
+		list{{$parent.Name}}{{.Entity}}Response, err := gql.List{{$parent.Name}}{{.Entities}}(ctx, conn.selectColumns(d, {{lowerFirst .Entity}}ColumnFields), &{{lowerFirst $parent.Name}}Id, pageSize, endCursor, true, filters)
+		if err != nil {
+			plugin.Logger(ctx).Error("{{.Name}}.list{{.Entities}}By{{$parent.Name}}", "api_error", err)
+			return nil, err
+		}
+		if list{{$parent.Name}}{{.Entity}}Response.{{$parent.Name}} == nil {
+			return nil, nil
+		}
+		for _, node := range list{{$parent.Name}}{{.Entity}}Response.{{$parent.Name}}.{{.Entities}}.Nodes {
+			d.StreamListItem(ctx, node)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*list{{$parent.Name}}{{.Entity}}Response.{{$parent.Name}}.{{.Entities}}.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *list{{$parent.Name}}{{.Entity}}Response.{{$parent.Name}}.{{.Entities}}.PageInfo.EndCursor
+	}
+
+	return nil, nil
+}
+
+{{end}}{{end}}// HYDRATE FUNCTION
+
+func get{{.Entity}}(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	id := d.EqualsQualString("id")
+
+	// check if id is empty
+	if id == "" {
+		return nil, nil
+	}
+
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("{{.Name}}.get{{.Entity}}", "connection_error", err)
+		return nil, err
+	}
+
+	get{{.Entity}}Response, err := gql.Get{{.Entity}}(ctx, conn.selectColumns(d, {{lowerFirst .Entity}}ColumnFields), &id)
+	if err != nil {
+		plugin.Logger(ctx).Error("{{.Name}}.get{{.Entity}}", "api_error", err)
+		return nil, err
+	}
+
+	return get{{.Entity}}Response.{{.GetField}}, nil
+}
+
+// {{lowerFirst .Entity}}QualFilters are the key columns pushed down to the filter of list calls
+var {{lowerFirst .Entity}}QualFilters = qualFilters{
+{{- range .Filters}}
+	"{{.Column}}": {field: "{{.Path}}"},
+{{- end}}
+}
+
+// Set the requested filter
+func set{{.Entity}}Filters(d *plugin.QueryData, ctx context.Context) gql.{{.Filter}} {
+	var filter gql.{{.Filter}}
+	setFilters(d, &filter, {{lowerFirst .Entity}}QualFilters)
+
+	return filter
+}
+`))
@@ -0,0 +1,101 @@
+package gql
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// sources are the bundled schema and the queries, read to find the deprecated
+// fields the queries select.
+//
+//go:embed schema.graphql genqlient.graphql tables.graphql
+var sources embed.FS
+
+// queryFiles are the query files of genqlient.yaml.
+var queryFiles = []string{"genqlient.graphql", "tables.graphql"}
+
+// DeprecatedField is a field selected by the queries which the bundled schema
+// marks as deprecated.
+type DeprecatedField struct {
+	// Field is the type and name of the field, such as "Team.cycleCalenderUrl"
+	Field  string
+	Reason string
+	// Operations are the queries selecting the field
+	Operations []string
+}
+
+var deprecatedFields = sync.OnceValues(findDeprecatedFields)
+
+// DeprecatedFields returns the deprecated fields the queries select, sorted by
+// field.
+func DeprecatedFields() ([]DeprecatedField, error) {
+	return deprecatedFields()
+}
+
+func findDeprecatedFields() ([]DeprecatedField, error) {
+	source, err := sources.ReadFile("schema.graphql")
+	if err != nil {
+		return nil, err
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(source)})
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	found := map[string]*DeprecatedField{}
+	var collect func(operation string, selections ast.SelectionSet)
+	collect = func(operation string, selections ast.SelectionSet) {
+		for _, selection := range selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				if s.Definition == nil {
+					continue
+				}
+				if deprecated := s.Definition.Directives.ForName("deprecated"); deprecated != nil {
+					key := s.ObjectDefinition.Name + "." + s.Name
+					f := found[key]
+					if f == nil {
+						f = &DeprecatedField{Field: key}
+						if reason := deprecated.Arguments.ForName("reason"); reason != nil {
+							f.Reason = strings.Join(strings.Fields(reason.Value.Raw), " ")
+						}
+						found[key] = f
+					}
+					if !slices.Contains(f.Operations, operation) {
+						f.Operations = append(f.Operations, operation)
+					}
+				}
+				collect(operation, s.SelectionSet)
+			case *ast.InlineFragment:
+				collect(operation, s.SelectionSet)
+			}
+		}
+	}
+	for _, name := range queryFiles {
+		source, err := sources.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		doc, errs := gqlparser.LoadQuery(schema, string(source))
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%s: %w", name, errs)
+		}
+		for _, operation := range doc.Operations {
+			collect(operation.Name, operation.SelectionSet)
+		}
+	}
+
+	fields := make([]DeprecatedField, 0, len(found))
+	for _, f := range found {
+		fields = append(fields, *f)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields, nil
+}
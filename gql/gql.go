@@ -1,5 +1,8 @@
 package gql
 
+//go:generate go run ../cmd/tablegen -schema schema.graphql -specs tables -queries tables.graphql -wrappers tables.go -tables ../linear
+//go:generate go run github.com/Khan/genqlient genqlient.yaml
+
 import (
 	"context"
 
@@ -9,21 +12,36 @@ import (
 type ListIssuesNodes = listIssueLabelsIssueLabelsIssueLabelConnectionNodesIssueLabelIssuesIssueConnectionNodesIssue
 type GetIssuesNode = getIssueIdsIssueLabelIssuesIssueConnectionNodesIssue
 type GetIssueNode = getIssueLabelIssueLabelIssuesIssueConnectionNodesIssue
+type IssueLabelNode = listIssueLabelsIssueLabelsIssueLabelConnectionNodesIssueLabel
+type ProjectTeamNode = listProjectTeamsProjectTeamsTeamConnectionNodesTeam
+type ProjectMemberNode = listProjectMembersProjectMembersUserConnectionNodesUser
+type IssueNode = listIssuesIssuesIssueConnectionNodesIssue
+type BatchTeam = listTeamsByIdsTeamsTeamConnectionNodesTeam
+type BatchUser = listUsersByIdsUsersUserConnectionNodesUser
+type BatchProject = listProjectsByIdsProjectsProjectConnectionNodesProject
 
 func ListIssues(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *IssueFilter) (*listIssuesResponse, error) {
 	return listIssues(ctx, client, first, after, includeArchived, filter)
 }
 
-func GetIssue(ctx context.Context, client graphql.Client, id *string) (*getIssueResponse, error) {
-	return getIssue(ctx, client, id)
+func GetIssueCreatedAtRange(ctx context.Context, client graphql.Client, includeArchived bool, filter *IssueFilter) (*getIssueCreatedAtRangeResponse, error) {
+	return getIssueCreatedAtRange(ctx, client, includeArchived, filter)
 }
 
-func ListAttachments(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *AttachmentFilter) (*listAttachmentsResponse, error) {
-	return listAttachments(ctx, client, first, after, includeArchived, filter)
+func ListTeamsByIds(ctx context.Context, client graphql.Client, first int, after string, filter *TeamFilter) (*listTeamsByIdsResponse, error) {
+	return listTeamsByIds(ctx, client, first, after, filter)
 }
 
-func GetAttachment(ctx context.Context, client graphql.Client, id *string) (*getAttachmentResponse, error) {
-	return getAttachment(ctx, client, id)
+func ListUsersByIds(ctx context.Context, client graphql.Client, first int, after string, filter *UserFilter) (*listUsersByIdsResponse, error) {
+	return listUsersByIds(ctx, client, first, after, filter)
+}
+
+func ListProjectsByIds(ctx context.Context, client graphql.Client, first int, after string, filter *ProjectFilter) (*listProjectsByIdsResponse, error) {
+	return listProjectsByIds(ctx, client, first, after, filter)
+}
+
+func GetIssue(ctx context.Context, client graphql.Client, id *string) (*getIssueResponse, error) {
+	return getIssue(ctx, client, id)
 }
 
 func ListComments(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *CommentFilter) (*listCommentsResponse, error) {
@@ -34,6 +52,10 @@ func GetComment(ctx context.Context, client graphql.Client, id *string) (*getCom
 	return getComment(ctx, client, id)
 }
 
+func ListCommentChildren(ctx context.Context, client graphql.Client, commentId *string, first int, after string, includeArchived bool, filter *CommentFilter) (*listCommentChildrenResponse, error) {
+	return listCommentChildren(ctx, client, commentId, first, after, includeArchived, filter)
+}
+
 func ListIntegrations(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool) (*listIntegrationsResponse, error) {
 	return listIntegrations(ctx, client, first, after, includeArchived)
 }
@@ -50,6 +72,14 @@ func GetTeamMembership(ctx context.Context, client graphql.Client, id *string) (
 	return getTeamMembership(ctx, client, id)
 }
 
+func ListTeamMembershipsByTeam(ctx context.Context, client graphql.Client, teamId *string, first int, after string, includeArchived bool) (*listTeamMembershipsByTeamResponse, error) {
+	return listTeamMembershipsByTeam(ctx, client, teamId, first, after, includeArchived)
+}
+
+func ListTeamMembershipsByUser(ctx context.Context, client graphql.Client, userId *string, first int, after string, includeArchived bool) (*listTeamMembershipsByUserResponse, error) {
+	return listTeamMembershipsByUser(ctx, client, userId, first, after, includeArchived)
+}
+
 func ListProjects(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *ProjectFilter) (*listProjectsResponse, error) {
 	return listProjects(ctx, client, first, after, includeArchived, filter)
 }
@@ -58,6 +88,18 @@ func GetProject(ctx context.Context, client graphql.Client, id *string) (*getPro
 	return getProject(ctx, client, id)
 }
 
+func ListTeamProjects(ctx context.Context, client graphql.Client, teamId *string, first int, after string, includeArchived bool, filter *ProjectFilter) (*listTeamProjectsResponse, error) {
+	return listTeamProjects(ctx, client, teamId, first, after, includeArchived, filter)
+}
+
+func ListProjectTeams(ctx context.Context, client graphql.Client, projectId *string, first int, after string, includeArchived bool) (*listProjectTeamsResponse, error) {
+	return listProjectTeams(ctx, client, projectId, first, after, includeArchived)
+}
+
+func ListProjectMembers(ctx context.Context, client graphql.Client, projectId *string, first int, after string, includeArchived bool) (*listProjectMembersResponse, error) {
+	return listProjectMembers(ctx, client, projectId, first, after, includeArchived)
+}
+
 func ListUsers(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *UserFilter) (*listUsersResponse, error) {
 	return listUsers(ctx, client, first, after, includeArchived, filter)
 }
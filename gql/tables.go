@@ -0,0 +1,21 @@
+// Code generated by tablegen. DO NOT EDIT.
+
+package gql
+
+import (
+	"context"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+func ListAttachments(ctx context.Context, client graphql.Client, first int, after string, includeArchived bool, filter *AttachmentFilter) (*listAttachmentsResponse, error) {
+	return listAttachments(ctx, client, first, after, includeArchived, filter)
+}
+
+func ListIssueAttachments(ctx context.Context, client graphql.Client, issueId *string, first int, after string, includeArchived bool, filter *AttachmentFilter) (*listIssueAttachmentsResponse, error) {
+	return listIssueAttachments(ctx, client, issueId, first, after, includeArchived, filter)
+}
+
+func GetAttachment(ctx context.Context, client graphql.Client, id *string) (*getAttachmentResponse, error) {
+	return getAttachment(ctx, client, id)
+}
@@ -0,0 +1,79 @@
+table "linear_attachment" {
+  description = "Linear Attachment"
+  type        = "Attachment"
+  list        = "attachments"
+  get         = "attachment"
+  title       = "title"
+  fields = [
+    "archivedAt",
+    "createdAt",
+    "groupBySource",
+    "metadata",
+    "source",
+    "sourceType",
+    "subtitle",
+    "updatedAt",
+    "url",
+  ]
+
+  relation "creator" {
+    fields = [
+      "id",
+      "active",
+      "admin",
+      "archivedAt",
+      "avatarUrl",
+      "calendarHash",
+      "createdAt",
+      "createdIssueCount",
+      "description",
+      "disableReason",
+      "displayName",
+      "email",
+      "guest",
+      "inviteHash",
+      "isMe",
+      "lastSeen",
+      "name",
+      "statusEmoji",
+      "statusLabel",
+      "statusUntilAt",
+      "timezone",
+      "updatedAt",
+      "url",
+    ]
+  }
+
+  relation "issue" {
+    fields = [
+      "id",
+      "createdAt",
+      "updatedAt",
+      "archivedAt",
+      "number",
+      "title",
+      "description",
+      "priority",
+      "estimate",
+      "sortOrder",
+      "startedAt",
+      "completedAt",
+      "canceledAt",
+      "autoClosedAt",
+      "autoArchivedAt",
+      "dueDate",
+      "trashed",
+      "snoozedUntilAt",
+      "previousIdentifiers",
+      "subIssueSortOrder",
+      "priorityLabel",
+      "identifier",
+      "url",
+      "branchName",
+      "customerTicketCount",
+    ]
+  }
+
+  # issue_id quals list the attachments of the issue
+  parent "issue" {}
+}
@@ -0,0 +1,295 @@
+package linear
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultResponseCacheMaxAge is the age after which cached scans are
+	// fetched again in full, so entities deleted since are dropped
+	defaultResponseCacheMaxAge = 24 * time.Hour
+	// cacheClockSkew is subtracted from the time of the last fetch when
+	// asking for the entities updated since, to allow for clock drift
+	cacheClockSkew = time.Minute
+)
+
+// responseCache persists the entities returned by full scans on disk, so that
+// later scans only fetch the entities updated since the last fetch.
+type responseCache struct {
+	dir    string
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// scanHeader starts a cached scan, and is followed by the entities of the
+// scan as a stream of JSON values, so that scans are read and written one
+// entity at a time.
+type scanHeader struct {
+	// ScannedAt is the time of the last full scan
+	ScannedAt time.Time `json:"scannedAt"`
+	// FetchedAt is the time of the last fetch, full or of the updates only
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+var unsafePathCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func newResponseCache(connectionName string, config linearConfig) *responseCache {
+	if config.ResponseCacheDir == nil || *config.ResponseCacheDir == "" {
+		return nil
+	}
+	maxAge := defaultResponseCacheMaxAge
+	if config.ResponseCacheMaxAge != nil {
+		maxAge = time.Duration(*config.ResponseCacheMaxAge) * time.Second
+	}
+	return &responseCache{
+		dir:    filepath.Join(*config.ResponseCacheDir, unsafePathCharacters.ReplaceAllString(connectionName, "_")),
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
+
+// cacheKey identifies a scan by its operation, the fields it selects and its
+// variables.
+func cacheKey(operation string, fields map[string]bool, variables interface{}) (string, error) {
+	var selected []string
+	for field := range fields {
+		selected = append(selected, field)
+	}
+	sort.Strings(selected)
+
+	key, err := json.Marshal(struct {
+		Operation string      `json:"operation"`
+		Fields    []string    `json:"fields"`
+		Variables interface{} `json:"variables"`
+	}{operation, selected, variables})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(key)
+	return operation + "-" + hex.EncodeToString(sum[:]), nil
+}
+
+// scanReader reads the entities of a cached scan.
+type scanReader[T any] struct {
+	scanHeader
+	file    *os.File
+	decoder *json.Decoder
+}
+
+// openScan opens the cached scan of the key. It returns nil when there is
+// none, or when it is older than the maximum age.
+func openScan[T any](c *responseCache, key string) *scanReader[T] {
+	file, err := os.Open(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	r := &scanReader[T]{file: file, decoder: json.NewDecoder(file)}
+	if err := r.decoder.Decode(&r.scanHeader); err != nil || c.now().Sub(r.ScannedAt) > c.maxAge {
+		file.Close()
+		return nil
+	}
+	return r
+}
+
+// next returns the next entity of the scan, and false at its end.
+func (r *scanReader[T]) next() (T, bool, error) {
+	var node T
+	if err := r.decoder.Decode(&node); err != nil {
+		if errors.Is(err, io.EOF) {
+			return node, false, nil
+		}
+		return node, false, err
+	}
+	return node, true, nil
+}
+
+func (r *scanReader[T]) close() {
+	r.file.Close()
+}
+
+// scanWriter writes a scan to a temporary file, which replaces the cached
+// scan of its key once the scan is complete. The shards of a scan may write
+// concurrently.
+type scanWriter[T any] struct {
+	cache *responseCache
+	name  string
+
+	mu      sync.Mutex
+	file    *os.File
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+	// err is the first error writing the scan, returned by commit so that a
+	// cache which cannot be written does not fail the query
+	err error
+}
+
+// createScan starts writing the scan of the key.
+func createScan[T any](c *responseCache, key string, header scanHeader) *scanWriter[T] {
+	w := &scanWriter[T]{cache: c, name: key + ".json"}
+	if w.err = os.MkdirAll(c.dir, 0700); w.err != nil {
+		return w
+	}
+	if w.file, w.err = os.CreateTemp(c.dir, w.name+".*.tmp"); w.err != nil {
+		return w
+	}
+	w.buffer = bufio.NewWriter(w.file)
+	w.encoder = json.NewEncoder(w.buffer)
+	w.err = w.encoder.Encode(header)
+	return w
+}
+
+// write appends the entities to the scan. Scans which are not cached are
+// written to a nil scanWriter.
+func (w *scanWriter[T]) write(nodes ...T) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, node := range nodes {
+		if w.err != nil {
+			return
+		}
+		w.err = w.encoder.Encode(node)
+	}
+}
+
+// commit replaces the cached scan of the key with the written one, and
+// prunes the cache.
+func (w *scanWriter[T]) commit() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return w.err
+	}
+	if w.err == nil {
+		w.err = w.buffer.Flush()
+	}
+	if err := w.file.Close(); w.err == nil {
+		w.err = err
+	}
+	if w.err == nil {
+		w.err = os.Rename(w.file.Name(), filepath.Join(w.cache.dir, w.name))
+	}
+	if w.err != nil {
+		os.Remove(w.file.Name())
+	}
+	w.file = nil
+	if w.err != nil {
+		return w.err
+	}
+	return w.cache.prune()
+}
+
+// abort discards the written scan, such as the partial scan of a query which
+// stopped early.
+func (w *scanWriter[T]) abort() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file != nil {
+		w.file.Close()
+		os.Remove(w.file.Name())
+		w.file = nil
+	}
+}
+
+// prune removes the files of the cache not written within the maximum age:
+// the scans of queries no longer made, which would be fetched again in full
+// anyway, and the temporary files of interrupted writes.
+func (c *responseCache) prune() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || c.now().Sub(info.ModTime()) <= c.maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeFileAtomic replaces the file of the directory at once, so concurrent
+// readers never see a partial file.
+func writeFileAtomic(dir string, name string, data []byte) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(dir, name))
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(file.Name()))
+	}
+	return nil
+}
+
+// mergeUpdates yields the cached entities with the entities updated since
+// the last fetch applied, until yield returns false. Updated entities still
+// matching the scan replace their cached version or are added, and changed
+// entities no longer matching it are dropped.
+func mergeUpdates[T any](cached *scanReader[T], updated []T, changedIds []string, id func(T) string, yield func(T) bool) error {
+	changed := map[string]bool{}
+	for _, changedId := range changedIds {
+		changed[changedId] = true
+	}
+	updates := map[string]T{}
+	for _, node := range updated {
+		updates[id(node)] = node
+		changed[id(node)] = true
+	}
+
+	for {
+		node, ok, err := cached.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		nodeId := id(node)
+		if update, ok := updates[nodeId]; ok {
+			delete(updates, nodeId)
+			if !yield(update) {
+				return nil
+			}
+		} else if !changed[nodeId] {
+			if !yield(node) {
+				return nil
+			}
+		}
+	}
+	for _, node := range updated {
+		if _, ok := updates[id(node)]; ok {
+			if !yield(node) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeUpdates(t *testing.T) {
+	cache := &responseCache{dir: t.TempDir(), maxAge: time.Hour, now: time.Now}
+	scan := createScan[string](cache, "scan", scanHeader{ScannedAt: cache.now()})
+	scan.write("a", "b", "c")
+	if err := scan.commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// b was updated, c no longer matches and d is new
+	updated := []string{"b", "d"}
+	changed := []string{"b", "c", "d"}
+	merge := func(stopAfter int) []string {
+		cached := openScan[string](cache, "scan")
+		if cached == nil {
+			t.Fatal("the scan was not cached")
+		}
+		defer cached.close()
+		var got []string
+		err := mergeUpdates(cached, updated, changed, func(id string) string { return id }, func(id string) bool {
+			got = append(got, id)
+			return len(got) < stopAfter
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	if got, want := merge(10), []string{"a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := merge(2), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v when stopping after 2, want %v", got, want)
+	}
+}
+
+func TestResponseCachePrunesOldFiles(t *testing.T) {
+	cache := &responseCache{dir: t.TempDir(), maxAge: time.Hour, now: time.Now}
+	stale := filepath.Join(cache.dir, "listIssues-stale.json")
+	interrupted := filepath.Join(cache.dir, "listIssues-fresh.json.123.tmp")
+	for _, file := range []string{stale, interrupted} {
+		if err := os.WriteFile(file, []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		old := cache.now().Add(-2 * time.Hour)
+		if err := os.Chtimes(file, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scan := createScan[string](cache, "listIssues-fresh", scanHeader{ScannedAt: cache.now()})
+	scan.write("a")
+	if err := scan.commit(); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(cache.dir, "*"))
+	if want := []string{filepath.Join(cache.dir, "listIssues-fresh.json")}; !reflect.DeepEqual(files, want) {
+		t.Errorf("got files %v, want %v", files, want)
+	}
+}
+
+func TestListIssuesResponseCacheStoppedEarly(t *testing.T) {
+	dir := t.TempDir()
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{ResponseCacheDir: &dir})
+
+	// the query stops after its first row, before the next page is fetched
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+	streamListItem := q.StreamListItem
+	q.StreamListItem = func(ctx context.Context, items ...interface{}) {
+		streamListItem(ctx, items...)
+		cancel()
+	}
+	if _, err := listIssues(ctx, q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(q.rows))
+	}
+	if ops := m.Operations(); !slices.Equal(ops[:1], []string{"listIssues"}) || slices.Contains(ops[1:], "listIssues") {
+		t.Errorf("got operations %v, want a single page", ops)
+	}
+	// the partial scan is not cached
+	if files, _ := filepath.Glob(filepath.Join(dir, "*", "*")); len(files) != 0 {
+		t.Errorf("got cache files %v, want none", files)
+	}
+}
+
+func TestListIssuesResponseCacheSharded(t *testing.T) {
+	dir := t.TempDir()
+	m := newMockLinear(t)
+	config := linearConfig{ResponseCacheDir: &dir, IssueScanShards: ptr(int64(3))}
+
+	// scans with nothing cached are fetched in full, split by creation time
+	if _, err := listIssues(testContext(), newTestQuery(m, config, stringQual("title", "Done")).QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ops := m.Operations(); len(ops) == 0 || ops[0] != "getIssueCreatedAtRange" {
+		t.Fatalf("got operations %v, want the creation date range first", ops)
+	}
+
+	// and later scans only fetch the issues updated since
+	before := len(m.Operations())
+	q := newTestQuery(m, config, stringQual("title", "Done"))
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	// the shards return the same fixtures, which the updates deduplicate
+	if len(q.rows) != 3 {
+		t.Errorf("got %d rows, want 3", len(q.rows))
+	}
+	if ops := m.Operations()[before:]; slices.Contains(ops, "getIssueCreatedAtRange") {
+		t.Errorf("got operations %v, want the updated issues only", ops)
+	}
+}
+
+func TestListIssuesResponseCache(t *testing.T) {
+	dir := t.TempDir()
+	m := newMockLinear(t)
+	scanIssues := func(config linearConfig) []graphqlRequest {
+		before := len(m.Requests())
+		q := newTestQuery(m, config)
+		if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+			t.Fatal(err)
+		}
+		values := columnValues(t, tableLinearIssue(testContext()), q.rows, "id", "title", "team_key")
+		assertJSON(t, values, `[
+			{"id": "issue-1", "title": "First issue", "team_key": "ENG"},
+			{"id": "issue-2", "title": "Second issue", "team_key": "ENG"},
+			{"id": "issue-3", "title": "Third issue", "team_key": "ENG"}
+		]`)
+		var requests []graphqlRequest
+		for _, req := range m.Requests()[before:] {
+			if req.OperationName == "listIssues" {
+				requests = append(requests, req)
+			}
+		}
+		return requests
+	}
+	filters := func(requests []graphqlRequest) string {
+		var filters []string
+		for _, req := range requests {
+			filter, _ := json.Marshal(req.Variables["filter"])
+			filters = append(filters, string(filter))
+		}
+		return strings.Join(filters, " ")
+	}
+
+	// the first scan fetches everything
+	config := linearConfig{ResponseCacheDir: &dir}
+	if requests := scanIssues(config); strings.Contains(filters(requests), "updatedAt") {
+		t.Errorf("got filters %s, want a full scan", filters(requests))
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "listIssues-*.json"))
+	if len(files) != 1 {
+		t.Fatalf("got cache files %v, want one", files)
+	}
+
+	// the next scans only fetch the issues updated since
+	requests := scanIssues(config)
+	if len(requests) != 4 {
+		t.Errorf("got %d listIssues requests, want two pages of updates and two of changed ids", len(requests))
+	}
+	for _, req := range requests {
+		filter, _ := json.Marshal(req.Variables["filter"])
+		if !strings.Contains(string(filter), `"updatedAt":{"gt":`) {
+			t.Errorf("got filter %s, want issues updated since the last fetch", filter)
+		}
+	}
+
+	// cached scans older than the maximum age are fetched again in full
+	config.ResponseCacheMaxAge = ptr(int64(0))
+	if requests := scanIssues(config); strings.Contains(filters(requests), "updatedAt") {
+		t.Errorf("got filters %s, want a full scan", filters(requests))
+	}
+
+	// an unreadable cache falls back to a full scan
+	if err := os.WriteFile(files[0], []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config.ResponseCacheMaxAge = nil
+	if requests := scanIssues(config); strings.Contains(filters(requests), "updatedAt") {
+		t.Errorf("got filters %s, want a full scan", filters(requests))
+	}
+}
@@ -2,6 +2,7 @@ package linear
 
 import (
 	"context"
+	"errors"
 
 	"github.com/turbot/steampipe-plugin-linear/gql"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
@@ -19,9 +20,30 @@ func commonColumns(c []*plugin.Column) []*plugin.Column {
 			Hydrate:     getOrganizationId,
 			Transform:   transform.FromValue(),
 		},
+		{
+			Name:        "workspace",
+			Description: "The alias of the workspace in the connection config, for connections aggregating several workspaces.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromMatrixItem(matrixKeyWorkspace),
+		},
 	}, c...)
 }
 
+// addOrganizationKeyColumn lets organization_id quals reach every table, where
+// they prune the matrix items of the connection to the workspaces of the
+// organization. It is not a connection key column, as the SDK compares a single
+// value per connection, which connections aggregating several organizations do
+// not have.
+func addOrganizationKeyColumn(table *plugin.Table) {
+	keyColumn := &plugin.KeyColumn{Name: matrixKeyOrganizationId, Require: plugin.Optional}
+	if table.List != nil {
+		table.List.KeyColumns = append(table.List.KeyColumns, keyColumn)
+	}
+	if table.Get != nil {
+		table.Get.KeyColumns = append(table.Get.KeyColumns, keyColumn)
+	}
+}
+
 // if the caching is required other than per connection, build a cache key for the call and use it in Memoize.
 var getOrganizationIdMemoized = plugin.HydrateFunc(getOrganizationIdUncached).Memoize(memoize.WithCacheKeyFunction(getOrganizationIdCacheKey))
 
@@ -33,11 +55,15 @@ func getOrganizationId(ctx context.Context, d *plugin.QueryData, h *plugin.Hydra
 
 // Build a cache key for the call to getOrganizationIdCacheKey.
 func getOrganizationIdCacheKey(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	key := "getOrganizationId"
+	key := "getOrganizationId-" + workspaceAlias(ctx)
 	return key, nil
 }
 
 func getOrganizationIdUncached(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	// the organization of the workspace was looked up to build the matrix
+	if organizationId, _ := plugin.GetMatrixItem(ctx)[matrixKeyOrganizationId].(string); organizationId != "" {
+		return organizationId, nil
+	}
 
 	conn, err := connect(ctx, d)
 	if err != nil {
@@ -51,5 +77,10 @@ func getOrganizationIdUncached(ctx context.Context, d *plugin.QueryData, h *plug
 		return nil, err
 	}
 
-	return getOrganizationResponse.GetOrganization().Id, nil
+	// a string, as the SDK compares connection key column values with quals
+	organizationId := getOrganizationResponse.GetOrganization().GetId()
+	if organizationId == nil {
+		return nil, errors.New("the organization has no id")
+	}
+	return *organizationId, nil
 }
@@ -1,12 +1,30 @@
 package linear
 
 import (
+	"errors"
+
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
 type linearConfig struct {
-	Token    *string `hcl:"token"`
-	PageSize *int64  `hcl:"page_size"`
+	Token               *string           `hcl:"token"`
+	TokenFile           *string           `hcl:"token_file"`
+	TokenCommand        *string           `hcl:"token_command"`
+	PageSize            *int64            `hcl:"page_size"`
+	Endpoint            *string           `hcl:"endpoint"`
+	ClientID            *string           `hcl:"client_id"`
+	ClientSecret        *string           `hcl:"client_secret"`
+	RefreshToken        *string           `hcl:"refresh_token"`
+	OAuthTokenURL       *string           `hcl:"oauth_token_url"`
+	RequestTimeout      *int64            `hcl:"request_timeout"`
+	MaxRetries          *int64            `hcl:"max_retries"`
+	HTTPProxy           *string           `hcl:"http_proxy"`
+	CABundle            *string           `hcl:"ca_bundle"`
+	IssueScanShards     *int64            `hcl:"issue_scan_shards"`
+	ResponseCacheDir    *string           `hcl:"response_cache_dir"`
+	ResponseCacheMaxAge *int64            `hcl:"response_cache_max_age"`
+	SyncStateDir        *string           `hcl:"sync_state_dir"`
+	Workspaces          map[string]string `hcl:"workspaces,optional"`
 }
 
 func ConfigInstance() interface{} {
@@ -14,6 +32,16 @@ func ConfigInstance() interface{} {
 }
 
 // GetConfig :: retrieve and cast connection config from query data
+//
+// The credentials of a connection are taken from the first of:
+//  1. `client_id` and `client_secret`, for an OAuth application
+//  2. `token`
+//  3. `token_file`, read again whenever the file changes
+//  4. `token_command`, run again when the API rejects its token
+//  5. the LINEAR_TOKEN environment variable
+//
+// Connections with `workspaces` query each workspace with its own token
+// instead, and may not set any of the above.
 func GetConfig(connection *plugin.Connection) linearConfig {
 	if connection == nil || connection.Config == nil {
 		return linearConfig{}
@@ -21,3 +49,12 @@ func GetConfig(connection *plugin.Connection) linearConfig {
 	config, _ := connection.Config.(linearConfig)
 	return config
 }
+
+// validateConfig rejects connection configs whose options contradict each
+// other.
+func validateConfig(config linearConfig) error {
+	if len(config.Workspaces) > 0 && (config.Token != nil || config.TokenFile != nil || config.TokenCommand != nil || config.ClientID != nil) {
+		return errors.New("'workspaces' cannot be combined with 'token', 'token_file', 'token_command' or 'client_id' in the connection configuration, as each workspace is queried with its own token. Edit your connection configuration file and then restart Steampipe")
+	}
+	return nil
+}
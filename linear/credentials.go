@@ -0,0 +1,156 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentials provide the Authorization header of a connection's requests.
+type credentials interface {
+	// authorization returns the value of the Authorization header
+	authorization(ctx context.Context) (string, error)
+	// invalidate discards an authorization the API rejected, and reports
+	// whether another one may be obtained
+	invalidate(authorization string) bool
+}
+
+// getCredentials returns the credentials of the connection, following the
+// precedence documented on GetConfig.
+func getCredentials(connectionName string, config linearConfig) (credentials, error) {
+	switch {
+	case config.ClientID != nil:
+		if config.ClientSecret == nil {
+			return nil, errors.New("'client_secret' must be set in the connection configuration when 'client_id' is set. Edit your connection configuration file and then restart Steampipe")
+		}
+		refreshToken, tokenURL := "", defaultOAuthTokenURL
+		if config.RefreshToken != nil {
+			refreshToken = *config.RefreshToken
+		}
+		if config.OAuthTokenURL != nil {
+			tokenURL = *config.OAuthTokenURL
+		}
+		// token requests go through the proxy, CA bundle and timeout of the
+		// connection, like its API requests
+		transport, err := newBaseTransport(config)
+		if err != nil {
+			return nil, err
+		}
+		client := &http.Client{Transport: transport, Timeout: requestTimeout(config)}
+		return getOAuthTokenSource(connectionName, client, *config.ClientID, *config.ClientSecret, refreshToken, tokenURL), nil
+	case config.Token != nil && *config.Token != "":
+		return staticToken(*config.Token), nil
+	case config.TokenFile != nil && *config.TokenFile != "":
+		return &fileToken{path: *config.TokenFile}, nil
+	case config.TokenCommand != nil && *config.TokenCommand != "":
+		return &commandToken{command: *config.TokenCommand}, nil
+	case os.Getenv("LINEAR_TOKEN") != "":
+		return staticToken(os.Getenv("LINEAR_TOKEN")), nil
+	}
+	return nil, errors.New("'token', 'token_file', 'token_command' or 'client_id' and 'client_secret' must be set in the connection configuration. Edit your connection configuration file and then restart Steampipe")
+}
+
+// authorizationHeader returns the Authorization header for an API token.
+func authorizationHeader(token string) string {
+	// check if it is a personal access key
+	if strings.HasPrefix(token, "lin_api") {
+		return token
+	}
+	return "Bearer " + token
+}
+
+// staticToken is a token set in the connection config or the environment.
+type staticToken string
+
+func (t staticToken) authorization(context.Context) (string, error) {
+	return authorizationHeader(string(t)), nil
+}
+
+func (t staticToken) invalidate(string) bool {
+	return false
+}
+
+// fileToken is a token read from a file, which is read again whenever it
+// changes so that rotated tokens are picked up.
+type fileToken struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+func (t *fileToken) authorization(context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return "", fmt.Errorf("reading token_file: %v", err)
+	}
+	if t.token == "" || !info.ModTime().Equal(t.modTime) || info.Size() != t.size {
+		content, err := os.ReadFile(t.path)
+		if err != nil {
+			return "", fmt.Errorf("reading token_file: %v", err)
+		}
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			return "", fmt.Errorf("token_file %s is empty", t.path)
+		}
+		t.token, t.modTime, t.size = token, info.ModTime(), info.Size()
+	}
+	return authorizationHeader(t.token), nil
+}
+
+func (t *fileToken) invalidate(string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// the file may have been rotated within the resolution of its mod time
+	t.token = ""
+	return true
+}
+
+// commandToken is a token printed by a command, which is run again when the
+// API rejects the token.
+type commandToken struct {
+	command string
+
+	mu    sync.Mutex
+	token string
+}
+
+func (t *commandToken) authorization(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", t.command)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("running token_command: %v: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		t.token = strings.TrimSpace(stdout.String())
+		if t.token == "" {
+			return "", fmt.Errorf("token_command printed no token")
+		}
+	}
+	return authorizationHeader(t.token), nil
+}
+
+func (t *commandToken) invalidate(authorization string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if authorizationHeader(t.token) == authorization {
+		t.token = ""
+	}
+	return true
+}
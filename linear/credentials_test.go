@@ -0,0 +1,122 @@
+package linear
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetCredentialsPrecedence(t *testing.T) {
+	t.Setenv("LINEAR_TOKEN", "lin_api_env")
+
+	tests := []struct {
+		name   string
+		config linearConfig
+		want   credentials
+	}{
+		{"token over token_file", linearConfig{Token: ptr("lin_api_config"), TokenFile: ptr("/token")}, staticToken("lin_api_config")},
+		{"token_file over token_command", linearConfig{TokenFile: ptr("/token"), TokenCommand: ptr("echo token")}, &fileToken{path: "/token"}},
+		{"token_command over environment", linearConfig{TokenCommand: ptr("echo token")}, &commandToken{command: "echo token"}},
+		{"empty token", linearConfig{Token: ptr("")}, staticToken("lin_api_env")},
+		{"environment", linearConfig{}, staticToken("lin_api_env")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := getCredentials(t.Name(), test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+
+	t.Setenv("LINEAR_TOKEN", "")
+	if _, err := getCredentials(t.Name(), linearConfig{}); err == nil {
+		t.Error("expected an error without credentials")
+	}
+}
+
+func TestFileTokenReadsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken := func(token string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	token := &fileToken{path: path}
+	now := time.Now()
+
+	writeToken("lin_api_one", now)
+	if got, err := token.authorization(context.Background()); err != nil || got != "lin_api_one" {
+		t.Fatalf("got %q, %v, want lin_api_one", got, err)
+	}
+
+	writeToken("lin_api_two", now.Add(time.Second))
+	if got, err := token.authorization(context.Background()); err != nil || got != "lin_api_two" {
+		t.Fatalf("got %q, %v, want the rotated lin_api_two", got, err)
+	}
+
+	os.Remove(path)
+	if _, err := token.authorization(context.Background()); err == nil {
+		t.Error("expected an error for a missing token file")
+	}
+}
+
+func TestTokenFileAndCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("lin_api_file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config linearConfig
+		want   string
+	}{
+		{"token_file", linearConfig{TokenFile: &path}, "lin_api_file"},
+		{"token_command", linearConfig{TokenCommand: ptr("echo lin_api_command")}, "lin_api_command"},
+		{"oauth token_command", linearConfig{TokenCommand: ptr("echo oauth-token")}, "Bearer oauth-token"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			m.Authorize(test.want)
+			test.config.Token = ptr("")
+			q := newTestQuery(m, test.config)
+
+			if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTokenCommandRunsAgainOnUnauthorized(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "counter")
+	command := `n=$(cat ` + counter + ` 2>/dev/null || echo 0); n=$((n+1)); echo $n > ` + counter + `; echo lin_api_$n`
+
+	m := newMockLinear(t)
+	m.Authorize("lin_api_2")
+	q := newTestQuery(m, linearConfig{Token: ptr(""), TokenCommand: &command})
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	var authorizations []string
+	for _, req := range m.Requests() {
+		authorizations = append(authorizations, req.Authorization)
+	}
+	if !reflect.DeepEqual(authorizations, []string{"lin_api_1", "lin_api_2"}) {
+		t.Errorf("got authorizations %v, want lin_api_1 then lin_api_2", authorizations)
+	}
+}
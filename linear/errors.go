@@ -1,34 +1,169 @@
 package linear
 
 import (
+	"bytes"
 	"context"
-	"strings"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 
+	"github.com/Khan/genqlient/graphql"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
-// shouldIgnoreErrors:: function which returns an ErrorPredicate for linear API calls
-func shouldIgnoreErrors(notFoundErrors []string) plugin.ErrorPredicateWithContext {
-	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
-		for _, pattern := range notFoundErrors {
-			// handle not found error
-			if strings.Contains(err.Error(), pattern) {
-				return true
+// errorCode is the classification of an error returned by the Linear API,
+// taken from the `extensions.code` of its GraphQL errors
+type errorCode string
+
+const (
+	errorCodeUnknown         errorCode = ""
+	errorCodeRateLimited     errorCode = "RATELIMITED"
+	errorCodeForbidden       errorCode = "FORBIDDEN"
+	errorCodeEntityNotFound  errorCode = "ENTITY_NOT_FOUND"
+	errorCodeQueryTooComplex errorCode = "QUERY_TOO_COMPLEX"
+	errorCodeAuthentication  errorCode = "AUTHENTICATION_ERROR"
+)
+
+// apiError is a non-200 response from the Linear API. genqlient only reports
+// these as a formatted string, so apiErrorClient returns them instead to keep
+// the status code and the GraphQL errors in the body.
+type apiError struct {
+	StatusCode int
+	Errors     gqlerror.List
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("returned error %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+func (e *apiError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors
+}
+
+// failedResponse is the last non-200 response of a request, recorded by
+// failedResponseTransport for apiErrorClient.
+type failedResponse struct {
+	statusCode int
+	body       []byte
+}
+
+type failedResponseKey struct{}
+
+// apiErrorClient turns the errors genqlient reports for non-200 responses into
+// an *apiError.
+type apiErrorClient struct {
+	wrapped graphql.Client
+}
+
+func (c *apiErrorClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	failed := &failedResponse{}
+	err := c.wrapped.MakeRequest(context.WithValue(ctx, failedResponseKey{}, failed), req, resp)
+	if err == nil || failed.statusCode == 0 {
+		return err
+	}
+	apiErr := &apiError{StatusCode: failed.statusCode, Body: string(failed.body)}
+
+	// the body is usually a GraphQL response carrying the error codes
+	var response struct {
+		Errors gqlerror.List `json:"errors"`
+	}
+	if json.Unmarshal(failed.body, &response) == nil {
+		apiErr.Errors = response.Errors
+	}
+	return apiErr
+}
+
+// failedResponseTransport records the status code and body of non-200
+// responses for apiErrorClient, and passes the responses on unchanged.
+type failedResponseTransport struct {
+	wrapped http.RoundTripper
+}
+
+func (t *failedResponseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.wrapped.RoundTrip(req)
+	if err != nil || resp.StatusCode == http.StatusOK {
+		return resp, err
+	}
+	failed, ok := req.Context().Value(failedResponseKey{}).(*failedResponse)
+	if !ok {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		body = []byte(fmt.Sprintf("<unreadable: %v>", err))
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	failed.statusCode = resp.StatusCode
+	failed.body = body
+	return resp, nil
+}
+
+// classifyError returns the code of the first classified GraphQL error in err,
+// falling back to the HTTP status code of the response.
+func classifyError(err error) errorCode {
+	var gqlErrors gqlerror.List
+	if errors.As(err, &gqlErrors) {
+		for _, gqlErr := range gqlErrors {
+			if code, ok := gqlErr.Extensions["code"].(string); ok && code != "" {
+				return errorCode(code)
 			}
 		}
-		return false
 	}
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			return errorCodeAuthentication
+		case http.StatusTooManyRequests:
+			return errorCodeRateLimited
+		case http.StatusForbidden:
+			return errorCodeForbidden
+		case http.StatusNotFound:
+			return errorCodeEntityNotFound
+		}
+	}
+
+	return errorCodeUnknown
+}
+
+// isComplexityError reports whether err is Linear rejecting a query for
+// exceeding the maximum query complexity.
+func isComplexityError(err error) bool {
+	return classifyError(err) == errorCodeQueryTooComplex
 }
 
-func shouldRetryError(retryErrors []string) plugin.ErrorPredicateWithContext {
+// shouldIgnoreErrors:: function which returns an ErrorPredicate for linear API calls
+func shouldIgnoreErrors(notFoundErrors []errorCode) plugin.ErrorPredicateWithContext {
 	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
+		return hasErrorCode(err, notFoundErrors)
+	}
+}
 
-		for _, pattern := range retryErrors {
-			// handle retry error
-			if strings.Contains(err.Error(), pattern) {
-				return true
-			}
-		}
+func shouldRetryError(retryErrors []errorCode) plugin.ErrorPredicateWithContext {
+	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
+		return hasErrorCode(err, retryErrors)
+	}
+}
+
+func hasErrorCode(err error, codes []errorCode) bool {
+	code := classifyError(err)
+	if code == errorCodeUnknown {
 		return false
 	}
-}
\ No newline at end of file
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package linear
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func gqlErrorWithCode(code string) *gqlerror.Error {
+	return &gqlerror.Error{Message: "some error", Extensions: map[string]interface{}{"code": code}}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errorCode
+	}{
+		{"graphql rate limited", gqlerror.List{gqlErrorWithCode("RATELIMITED")}, errorCodeRateLimited},
+		{"graphql forbidden", gqlerror.List{gqlErrorWithCode("FORBIDDEN")}, errorCodeForbidden},
+		{"graphql not found", gqlerror.List{gqlErrorWithCode("ENTITY_NOT_FOUND")}, errorCodeEntityNotFound},
+		{"graphql first coded error", gqlerror.List{{Message: "no code"}, gqlErrorWithCode("FORBIDDEN")}, errorCodeForbidden},
+		{"graphql without code", gqlerror.List{{Message: "no code"}}, errorCodeUnknown},
+		{"wrapped graphql", fmt.Errorf("listing: %w", gqlerror.List{gqlErrorWithCode("RATELIMITED")}), errorCodeRateLimited},
+		{"http body code", &apiError{StatusCode: http.StatusBadRequest, Errors: gqlerror.List{gqlErrorWithCode("RATELIMITED")}}, errorCodeRateLimited},
+		{"http 429", &apiError{StatusCode: http.StatusTooManyRequests}, errorCodeRateLimited},
+		{"http 403", &apiError{StatusCode: http.StatusForbidden}, errorCodeForbidden},
+		{"http 404", &apiError{StatusCode: http.StatusNotFound}, errorCodeEntityNotFound},
+		{"http 500", &apiError{StatusCode: http.StatusInternalServerError}, errorCodeUnknown},
+		{"message with digits", errors.New("issue 404 has 429 comments"), errorCodeUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := classifyError(test.err); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestShouldIgnoreErrors(t *testing.T) {
+	ignore := shouldIgnoreErrors([]errorCode{errorCodeEntityNotFound})
+
+	if !ignore(context.Background(), nil, nil, gqlerror.List{gqlErrorWithCode("ENTITY_NOT_FOUND")}) {
+		t.Error("not found error was not ignored")
+	}
+	if ignore(context.Background(), nil, nil, gqlerror.List{gqlErrorWithCode("FORBIDDEN")}) {
+		t.Error("forbidden error was ignored")
+	}
+	if ignore(context.Background(), nil, nil, errors.New("returned error 404 Not Found")) {
+		t.Error("unclassified error was ignored")
+	}
+}
+
+func TestShouldRetryError(t *testing.T) {
+	retry := shouldRetryError([]errorCode{errorCodeRateLimited})
+
+	if !retry(context.Background(), nil, nil, gqlerror.List{gqlErrorWithCode("RATELIMITED")}) {
+		t.Error("rate limited error was not retried")
+	}
+	if retry(context.Background(), nil, nil, &apiError{StatusCode: http.StatusBadRequest}) {
+		t.Error("400 error was retried")
+	}
+}
+
+func TestErrorPredicatesOnAPIErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(m *mockLinear)
+		code   errorCode
+		ignore bool
+		retry  bool
+	}{
+		{"http not found", func(m *mockLinear) { m.FailWith("getIssue", http.StatusNotFound) }, errorCodeEntityNotFound, true, false},
+		{"http rate limited", func(m *mockLinear) { m.FailWith("getIssue", http.StatusTooManyRequests) }, errorCodeRateLimited, false, true},
+		{"http server error", func(m *mockLinear) { m.FailWith("getIssue", http.StatusInternalServerError) }, errorCodeUnknown, false, false},
+		{"graphql not found", func(m *mockLinear) {
+			m.ErrorWith("getIssue", "Entity not found", map[string]interface{}{"code": "ENTITY_NOT_FOUND"})
+		}, errorCodeEntityNotFound, true, false},
+		{"graphql rate limited", func(m *mockLinear) {
+			m.ErrorWith("getIssue", "Rate limit exceeded", map[string]interface{}{"code": "RATELIMITED"})
+		}, errorCodeRateLimited, false, true},
+		{"graphql forbidden", func(m *mockLinear) {
+			m.ErrorWith("getIssue", "Forbidden", map[string]interface{}{"code": "FORBIDDEN"})
+		}, errorCodeForbidden, false, false},
+	}
+
+	plugin := Plugin(context.Background())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			test.setup(m)
+			q := newTestQuery(m, linearConfig{}, stringQual("id", "issue-1"))
+
+			_, err := getIssue(testContext(), q.QueryData, nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := classifyError(err); got != test.code {
+				t.Errorf("got code %q, want %q for %v", got, test.code, err)
+			}
+			if got := plugin.DefaultIgnoreConfig.ShouldIgnoreErrorFunc(context.Background(), q.QueryData, nil, err); got != test.ignore {
+				t.Errorf("got ignore %v, want %v for %v", got, test.ignore, err)
+			}
+			if got := plugin.DefaultRetryConfig.ShouldRetryErrorFunc(context.Background(), q.QueryData, nil, err); got != test.retry {
+				t.Errorf("got retry %v, want %v for %v", got, test.retry, err)
+			}
+		})
+	}
+}
+
+func TestIsComplexityError(t *testing.T) {
+	if !isComplexityError(&apiError{StatusCode: http.StatusBadRequest, Errors: gqlerror.List{gqlErrorWithCode("QUERY_TOO_COMPLEX")}}) {
+		t.Error("coded complexity error was not recognised")
+	}
+	if isComplexityError(gqlerror.List{{Message: "Query too complex - complexity is 12000, maximum allowed is 10000"}}) {
+		t.Error("uncoded error was recognised by its message")
+	}
+}
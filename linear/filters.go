@@ -0,0 +1,216 @@
+package linear
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+)
+
+// qualFilters maps the key columns of a table to the comparators of its
+// GraphQL filter their quals are pushed down to.
+type qualFilters map[string]qualFilter
+
+// qualFilter is the comparator a key column is pushed down to.
+type qualFilter struct {
+	// field is the path of the comparator in the filter, such as "CreatedAt",
+	// or "Lead.Id" for the identifier of a related entity
+	field string
+	// operators restricts the operators of the key column, for columns read
+	// back from their qual
+	operators []string
+}
+
+// comparatorFields are the comparator fields set by each qual operator. Lists
+// of values, from `in` and `not in`, set the list fields instead.
+var comparatorFields = []struct {
+	operator string
+	field    string
+	list     string
+}{
+	{quals.QualOperatorEqual, "Eq", "In"},
+	{quals.QualOperatorNotEqual, "Neq", "Nin"},
+	{quals.QualOperatorGreater, "Gt", ""},
+	{quals.QualOperatorGreaterOrEqual, "Gte", ""},
+	{quals.QualOperatorLess, "Lt", ""},
+	{quals.QualOperatorLessOrEqual, "Lte", ""},
+}
+
+// filterKeyColumns returns the optional key columns of the filters, with the
+// operators their comparators support. Comparators of nullable fields, or
+// of nullable related entities, also support `is null` and `is not null`.
+func filterKeyColumns[F any](filters qualFilters) []*plugin.KeyColumn {
+	filterType := reflect.TypeFor[F]()
+
+	var keyColumns []*plugin.KeyColumn
+	for column, filter := range filters {
+		types, ok := comparatorPath(filterType, filter.field)
+		if !ok {
+			panic("filter " + filterType.Name() + " has no comparator " + filter.field + " for column " + column)
+		}
+		operators := filter.operators
+		if operators == nil {
+			for _, f := range comparatorFields {
+				if _, ok := types[len(types)-1].FieldByName(f.field); ok {
+					operators = append(operators, f.operator)
+				}
+			}
+			if nullIndex(types) >= 0 {
+				operators = append(operators, quals.QualOperatorIsNull, quals.QualOperatorIsNotNull)
+			}
+		}
+		keyColumns = append(keyColumns, &plugin.KeyColumn{
+			Name:      column,
+			Require:   plugin.Optional,
+			Operators: operators,
+		})
+	}
+	sort.Slice(keyColumns, func(i, j int) bool { return keyColumns[i].Name < keyColumns[j].Name })
+	return keyColumns
+}
+
+// setFilters sets the comparators of the filter from the quals of the query.
+// Quals which can't be pushed down are left to Steampipe to apply.
+func setFilters[F any](d *plugin.QueryData, filter *F, filters qualFilters) {
+	for column, f := range filters {
+		if d.Quals[column] == nil {
+			continue
+		}
+		for _, q := range d.Quals[column].Quals {
+			setComparator(reflect.ValueOf(filter).Elem(), strings.Split(f.field, "."), q)
+		}
+	}
+}
+
+// setComparator sets the field of the comparator at the path for the qual.
+func setComparator(filter reflect.Value, path []string, q *quals.Qual) {
+	types, _ := comparatorPath(filter.Type(), strings.Join(path, "."))
+
+	switch q.Operator {
+	case quals.QualOperatorIsNull, quals.QualOperatorIsNotNull:
+		// the null check is set on the innermost nullable filter of the path,
+		// so `lead_id is null` matches the entities without a lead
+		i := nullIndex(types)
+		if i < 0 {
+			return
+		}
+		isNull := q.Operator == quals.QualOperatorIsNull
+		structAt(filter, path[:i+1]).FieldByName("Null").Set(reflect.ValueOf(&isNull))
+		return
+	}
+
+	for _, f := range comparatorFields {
+		if f.operator != q.Operator {
+			continue
+		}
+		name := f.field
+		if q.Value.GetListValue() != nil {
+			name = f.list
+		}
+		field, ok := types[len(types)-1].FieldByName(name)
+		if name == "" || !ok {
+			return
+		}
+		value, ok := comparatorValue(q.Value, field.Type)
+		if !ok {
+			return
+		}
+		structAt(filter, path).FieldByName(name).Set(value)
+		return
+	}
+}
+
+// comparatorPath returns the types of the filters along the path to a
+// comparator, the comparator last.
+func comparatorPath(filterType reflect.Type, path string) ([]reflect.Type, bool) {
+	var types []reflect.Type
+	current := filterType
+	for _, name := range strings.Split(path, ".") {
+		field, ok := current.FieldByName(name)
+		if !ok || field.Type.Kind() != reflect.Pointer || field.Type.Elem().Kind() != reflect.Struct {
+			return nil, false
+		}
+		current = field.Type.Elem()
+		types = append(types, current)
+	}
+	return types, true
+}
+
+// nullIndex returns the index of the innermost filter of the path with a
+// null check, or -1 when none has one.
+func nullIndex(types []reflect.Type) int {
+	for i := len(types) - 1; i >= 0; i-- {
+		if field, ok := types[i].FieldByName("Null"); ok && field.Type == reflect.TypeFor[*bool]() {
+			return i
+		}
+	}
+	return -1
+}
+
+// structAt returns the filter at the path, allocating the filters along it.
+func structAt(filter reflect.Value, path []string) reflect.Value {
+	current := filter
+	for _, name := range path {
+		field := current.FieldByName(name)
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		current = field.Elem()
+	}
+	return current
+}
+
+// comparatorValue converts the value of a qual to the type of a comparator
+// field, a pointer to a value or a list of pointers.
+func comparatorValue(value *proto.QualValue, fieldType reflect.Type) (reflect.Value, bool) {
+	if fieldType.Kind() == reflect.Slice {
+		list := reflect.MakeSlice(fieldType, 0, len(value.GetListValue().GetValues()))
+		for _, item := range value.GetListValue().GetValues() {
+			itemValue, ok := comparatorValue(item, fieldType.Elem())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			list = reflect.Append(list, itemValue)
+		}
+		return list, true
+	}
+
+	elemType := fieldType.Elem()
+	var v reflect.Value
+	switch {
+	case elemType == reflect.TypeFor[time.Time]():
+		if value.GetTimestampValue() == nil {
+			return reflect.Value{}, false
+		}
+		v = reflect.ValueOf(value.GetTimestampValue().AsTime())
+	case elemType.Kind() == reflect.Float64:
+		switch value.Value.(type) {
+		case *proto.QualValue_DoubleValue:
+			v = reflect.ValueOf(value.GetDoubleValue())
+		case *proto.QualValue_Int64Value:
+			v = reflect.ValueOf(float64(value.GetInt64Value()))
+		default:
+			return reflect.Value{}, false
+		}
+	case elemType.Kind() == reflect.String:
+		if _, ok := value.Value.(*proto.QualValue_StringValue); !ok {
+			return reflect.Value{}, false
+		}
+		v = reflect.ValueOf(value.GetStringValue())
+	case elemType.Kind() == reflect.Bool:
+		if _, ok := value.Value.(*proto.QualValue_BoolValue); !ok {
+			return reflect.Value{}, false
+		}
+		v = reflect.ValueOf(value.GetBoolValue())
+	default:
+		return reflect.Value{}, false
+	}
+
+	pointer := reflect.New(elemType)
+	pointer.Elem().Set(v.Convert(elemType))
+	return pointer, true
+}
@@ -0,0 +1,272 @@
+package linear
+
+import (
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-linear/gql"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+var (
+	jan1 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb1 = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestFilterPushdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		list   plugin.HydrateFunc
+		quals  []testQual
+		filter string
+	}{
+		{
+			name:   "linear_issue without quals",
+			list:   listIssues,
+			filter: `{}`,
+		},
+		{
+			name: "linear_issue comparisons",
+			list: listIssues,
+			quals: []testQual{
+				timeQual("created_at", ">=", jan1),
+				timeQual("created_at", "<", feb1),
+				doubleQual("priority", "=", 1),
+				doubleQual("number", ">", 10),
+				stringQual("title", "Broken login"),
+				timeQual("due_date", "<=", feb1),
+			},
+			filter: `{
+				"createdAt": {"gte": "2024-01-01T00:00:00Z", "lt": "2024-02-01T00:00:00Z"},
+				"priority": {"eq": 1},
+				"number": {"gt": 10},
+				"title": {"eq": "Broken login"},
+				"dueDate": {"lte": "2024-02-01T00:00:00Z"}
+			}`,
+		},
+		{
+			name: "linear_issue not equal and lists",
+			list: listIssues,
+			quals: []testQual{
+				doubleQual("priority", "<>", 0),
+				listQual("title", "=", "Broken login", "Slow search"),
+				listQual("title", "<>", "Flaky test"),
+			},
+			filter: `{
+				"priority": {"neq": 0},
+				"title": {"in": ["Broken login", "Slow search"], "nin": ["Flaky test"]}
+			}`,
+		},
+		{
+			name: "linear_issue null checks",
+			list: listIssues,
+			quals: []testQual{
+				nullQual("due_date", "is null"),
+				nullQual("started_at", "is not null"),
+			},
+			filter: `{"dueDate": {"null": true}, "startedAt": {"null": false}}`,
+		},
+		{
+			name: "linear_issue unassigned and unplanned",
+			list: listIssues,
+			quals: []testQual{
+				nullQual("assignee_id", "is null"),
+				nullQual("cycle_id", "is null"),
+				nullQual("project_id", "is not null"),
+				stringQual("team_id", "team-1"),
+			},
+			filter: `{
+				"assignee": {"null": true},
+				"cycle": {"null": true},
+				"project": {"null": false},
+				"team": {"id": {"eq": "team-1"}}
+			}`,
+		},
+		{
+			name: "linear_issue sla and triage",
+			list: listIssues,
+			quals: []testQual{
+				timeQual("triaged_at", ">", jan1),
+			},
+			filter: `{"triagedAt": {"gt": "2024-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:   "linear_issue derived sla status is not pushed down",
+			list:   listIssues,
+			quals:  []testQual{stringQual("sla_status", "Breached")},
+			filter: `{}`,
+		},
+		{
+			name: "linear_project",
+			list: listProjects,
+			quals: []testQual{
+				stringQual("state", "started"),
+				timeQual("start_date", ">=", jan1),
+				stringQual("lead_id", "user-1"),
+				stringQual("creator_id", "user-2"),
+				stringQual("member_id", "user-3"),
+				stringQual("roadmap_id", "roadmap-1"),
+			},
+			filter: `{
+				"state": {"eq": "started"},
+				"startDate": {"gte": "2024-01-01T00:00:00Z"},
+				"lead": {"id": {"eq": "user-1"}},
+				"creator": {"id": {"eq": "user-2"}},
+				"members": {"id": {"eq": "user-3"}},
+				"roadmaps": {"some": {"id": {"eq": "roadmap-1"}}}
+			}`,
+		},
+		{
+			name:   "linear_project without a lead",
+			list:   listProjects,
+			quals:  []testQual{nullQual("lead_id", "is null"), listQual("creator_id", "<>", "user-1", "user-2")},
+			filter: `{"lead": {"null": true}, "creator": {"id": {"nin": ["user-1", "user-2"]}}}`,
+		},
+		{
+			name: "linear_comment",
+			list: listComments,
+			quals: []testQual{
+				stringQual("issue_id", "issue-1"),
+				stringQual("user_id", "user-1"),
+				timeQual("updated_at", ">", jan1),
+			},
+			filter: `{
+				"issue": {"id": {"eq": "issue-1"}},
+				"user": {"id": {"eq": "user-1"}},
+				"updatedAt": {"gt": "2024-01-01T00:00:00Z"}
+			}`,
+		},
+		{
+			name:   "linear_attachment",
+			list:   listAttachments,
+			quals:  []testQual{stringQual("source_type", "github"), stringQual("url", "https://github.com/example/repo/pull/1")},
+			filter: `{"sourceType": {"eq": "github"}, "url": {"eq": "https://github.com/example/repo/pull/1"}}`,
+		},
+		{
+			name:   "linear_attachment without a creator",
+			list:   listAttachments,
+			quals:  []testQual{nullQual("creator_id", "is null"), nullQual("subtitle", "is null")},
+			filter: `{"creator": {"null": true}, "subtitle": {"null": true}}`,
+		},
+		{
+			name:   "linear_issue_label",
+			list:   listIssueLabels,
+			quals:  []testQual{stringQual("name", "Bug")},
+			filter: `{"name": {"eq": "Bug"}}`,
+		},
+		{
+			name:   "linear_team",
+			list:   listTeams,
+			quals:  []testQual{stringQual("key", "ENG"), timeQual("created_at", "=", jan1)},
+			filter: `{"key": {"eq": "ENG"}, "createdAt": {"eq": "2024-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:   "linear_user",
+			list:   listUsers,
+			quals:  []testQual{stringQual("email", "ada@example.com"), stringQual("display_name", "ada")},
+			filter: `{"email": {"eq": "ada@example.com"}, "displayName": {"eq": "ada"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			q := newTestQuery(m, linearConfig{}, test.quals...)
+
+			if _, err := test.list(testContext(), q.QueryData, nil); err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			requests := m.Requests()
+			if len(requests) == 0 {
+				t.Fatal("no request was sent")
+			}
+			assertJSON(t, requests[0].Variables["filter"], test.filter)
+		})
+	}
+}
+
+func TestFilterKeyColumns(t *testing.T) {
+	operators := map[string][]string{}
+	for _, keyColumn := range filterKeyColumns[gql.ProjectFilter](projectQualFilters) {
+		operators[keyColumn.Name] = keyColumn.Operators
+	}
+	assertJSON(t, operators, `{
+		"created_at": ["=", "<>", ">", ">=", "<", "<="],
+		"updated_at": ["=", "<>", ">", ">=", "<", "<="],
+		"name": ["=", "<>"],
+		"state": ["=", "<>"],
+		"slug_id": ["=", "<>"],
+		"start_date": ["=", "<>", ">", ">=", "<", "<=", "is null", "is not null"],
+		"target_date": ["=", "<>", ">", ">=", "<", "<=", "is null", "is not null"],
+		"lead_id": ["=", "<>", "is null", "is not null"],
+		"creator_id": ["=", "<>"],
+		"member_id": ["="],
+		"roadmap_id": ["="]
+	}`)
+
+	// every declared comparator exists in its filter
+	for _, keyColumns := range [][]*plugin.KeyColumn{
+		filterKeyColumns[gql.AttachmentFilter](attachmentQualFilters),
+		filterKeyColumns[gql.CommentFilter](commentQualFilters),
+		filterKeyColumns[gql.IssueFilter](issueQualFilters),
+		filterKeyColumns[gql.IssueLabelFilter](issueLabelQualFilters),
+		filterKeyColumns[gql.TeamFilter](teamQualFilters),
+		filterKeyColumns[gql.UserFilter](userQualFilters),
+	} {
+		if err := plugin.KeyColumnSlice(keyColumns).Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestKeyColumnsWalkParentConnection(t *testing.T) {
+	tests := []struct {
+		name      string
+		list      plugin.HydrateFunc
+		quals     []testQual
+		variables string
+	}{
+		{
+			name:      "linear_attachment by issue",
+			list:      listAttachments,
+			quals:     []testQual{stringQual("issue_id", "issue-1"), stringQual("title", "Pull request")},
+			variables: `{"issueId": "issue-1", "first": 50, "includeArchived": true, "filter": {"title": {"eq": "Pull request"}}}`,
+		},
+		{
+			name:      "linear_comment by parent",
+			list:      listComments,
+			quals:     []testQual{stringQual("parent_id", "comment-1")},
+			variables: `{"commentId": "comment-1", "first": 50, "includeArchived": true, "filter": {}}`,
+		},
+		{
+			name:      "linear_project by team",
+			list:      listProjects,
+			quals:     []testQual{stringQual("team_id", "team-1"), stringQual("name", "Launch")},
+			variables: `{"teamId": "team-1", "first": 50, "includeArchived": true, "filter": {"name": {"eq": "Launch"}}}`,
+		},
+		{
+			name:      "linear_team_membership by team",
+			list:      listTeamMemberships,
+			quals:     []testQual{stringQual("team_id", "team-1")},
+			variables: `{"teamId": "team-1", "first": 50, "includeArchived": true}`,
+		},
+		{
+			name:      "linear_team_membership by user",
+			list:      listTeamMemberships,
+			quals:     []testQual{stringQual("user_id", "user-1")},
+			variables: `{"userId": "user-1", "first": 50, "includeArchived": true}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			q := newTestQuery(m, linearConfig{}, test.quals...)
+
+			if _, err := test.list(testContext(), q.QueryData, nil); err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			assertJSON(t, m.Requests()[0].Variables, test.variables)
+		})
+	}
+}
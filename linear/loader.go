@@ -0,0 +1,174 @@
+package linear
+
+import (
+	"context"
+	"sync"
+
+	"github.com/turbot/steampipe-plugin-linear/gql"
+)
+
+// maxLoaderBatchSize bounds the number of ids requested at once by a loader
+const maxLoaderBatchSize = 250
+
+// entityLoader batches the fetching of entities by id, and caches them so each
+// distinct id is fetched at most once over the lifetime of the loader.
+type entityLoader[T any] struct {
+	fetch func(ctx context.Context, ids []string) ([]T, error)
+	id    func(T) string
+
+	mu      sync.Mutex
+	entries map[string]*loaderEntry[T]
+}
+
+type loaderEntry[T any] struct {
+	done  chan struct{}
+	value T
+	found bool
+	err   error
+}
+
+func newEntityLoader[T any](id func(T) string, fetch func(ctx context.Context, ids []string) ([]T, error)) *entityLoader[T] {
+	return &entityLoader[T]{
+		fetch:   fetch,
+		id:      id,
+		entries: map[string]*loaderEntry[T]{},
+	}
+}
+
+// loadMany returns the entities of the ids, keyed by id. Ids not yet loaded are
+// fetched in batches, and ids being fetched by another caller are waited for.
+// Ids of entities which do not exist are missing from the result.
+func (l *entityLoader[T]) loadMany(ctx context.Context, ids []string) (map[string]T, error) {
+	l.mu.Lock()
+	var missing []string
+	entries := map[string]*loaderEntry[T]{}
+	for _, id := range ids {
+		if _, ok := entries[id]; ok {
+			continue
+		}
+		entry, ok := l.entries[id]
+		if !ok {
+			entry = &loaderEntry[T]{done: make(chan struct{})}
+			l.entries[id] = entry
+			missing = append(missing, id)
+		}
+		entries[id] = entry
+	}
+	l.mu.Unlock()
+
+	if len(missing) > 0 {
+		l.load(ctx, missing)
+	}
+
+	values := map[string]T{}
+	for id, entry := range entries {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		if entry.found {
+			values[id] = entry.value
+		}
+	}
+	return values, nil
+}
+
+// load fetches the ids and completes their entries. Entries which failed are
+// dropped from the cache so a later caller tries them again.
+func (l *entityLoader[T]) load(ctx context.Context, ids []string) {
+	for start := 0; start < len(ids); start += maxLoaderBatchSize {
+		batch := ids[start:min(start+maxLoaderBatchSize, len(ids))]
+		values, err := l.fetch(ctx, batch)
+
+		l.mu.Lock()
+		found := map[string]T{}
+		for _, value := range values {
+			found[l.id(value)] = value
+		}
+		for _, id := range batch {
+			entry := l.entries[id]
+			entry.value, entry.found = found[id]
+			entry.err = err
+			if err != nil {
+				delete(l.entries, id)
+			}
+			close(entry.done)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// newTeamLoader returns a loader of teams, archived ones included.
+func newTeamLoader(conn *linearClient) *entityLoader[*gql.BatchTeam] {
+	return newEntityLoader(func(team *gql.BatchTeam) string { return *team.Id },
+		func(ctx context.Context, ids []string) ([]*gql.BatchTeam, error) {
+			var teams []*gql.BatchTeam
+			var endCursor string
+			filter := &gql.TeamFilter{Id: &gql.IDComparator{In: idPointers(ids)}}
+			for {
+				response, err := gql.ListTeamsByIds(ctx, conn.client, len(ids), endCursor, filter)
+				if err != nil {
+					return nil, err
+				}
+				teams = append(teams, response.Teams.Nodes...)
+				if !*response.Teams.PageInfo.HasNextPage {
+					return teams, nil
+				}
+				endCursor = *response.Teams.PageInfo.EndCursor
+			}
+		})
+}
+
+// newUserLoader returns a loader of users, disabled ones included.
+func newUserLoader(conn *linearClient) *entityLoader[*gql.BatchUser] {
+	return newEntityLoader(func(user *gql.BatchUser) string { return *user.Id },
+		func(ctx context.Context, ids []string) ([]*gql.BatchUser, error) {
+			var users []*gql.BatchUser
+			var endCursor string
+			filter := &gql.UserFilter{Id: &gql.IDComparator{In: idPointers(ids)}}
+			for {
+				response, err := gql.ListUsersByIds(ctx, conn.client, len(ids), endCursor, filter)
+				if err != nil {
+					return nil, err
+				}
+				users = append(users, response.Users.Nodes...)
+				if !*response.Users.PageInfo.HasNextPage {
+					return users, nil
+				}
+				endCursor = *response.Users.PageInfo.EndCursor
+			}
+		})
+}
+
+// newProjectLoader returns a loader of projects, archived ones included.
+func newProjectLoader(conn *linearClient) *entityLoader[*gql.BatchProject] {
+	return newEntityLoader(func(project *gql.BatchProject) string { return *project.Id },
+		func(ctx context.Context, ids []string) ([]*gql.BatchProject, error) {
+			var projects []*gql.BatchProject
+			var endCursor string
+			filter := &gql.ProjectFilter{Id: &gql.IDComparator{In: idPointers(ids)}}
+			for {
+				response, err := gql.ListProjectsByIds(ctx, conn.client, len(ids), endCursor, filter)
+				if err != nil {
+					return nil, err
+				}
+				projects = append(projects, response.Projects.Nodes...)
+				if !*response.Projects.PageInfo.HasNextPage {
+					return projects, nil
+				}
+				endCursor = *response.Projects.PageInfo.EndCursor
+			}
+		})
+}
+
+func idPointers(ids []string) []*string {
+	pointers := make([]*string, len(ids))
+	for i := range ids {
+		pointers[i] = &ids[i]
+	}
+	return pointers
+}
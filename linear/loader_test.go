@@ -0,0 +1,59 @@
+package linear
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestEntityLoader(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	fail := true
+	loader := newEntityLoader(func(id string) string { return id },
+		func(ctx context.Context, ids []string) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, append([]string(nil), ids...))
+			if fail {
+				return nil, errors.New("unavailable")
+			}
+			// missing entities are not returned
+			var found []string
+			for _, id := range ids {
+				if id != "missing" {
+					found = append(found, id)
+				}
+			}
+			return found, nil
+		})
+
+	// failed ids are fetched again by the next caller
+	if _, err := loader.loadMany(context.Background(), []string{"a"}); err == nil {
+		t.Fatal("expected the fetch error")
+	}
+	fail = false
+
+	values, err := loader.loadMany(context.Background(), []string{"a", "b", "a", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "a", "b": "b"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("got %v, want %v", values, want)
+	}
+
+	// loaded and missing ids are not fetched again
+	if _, err := loader.loadMany(context.Background(), []string{"b", "missing", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, batch := range batches {
+		sort.Strings(batch)
+	}
+	want := [][]string{{"a"}, {"a", "b", "missing"}, {"c"}}
+	if !reflect.DeepEqual(batches, want) {
+		t.Errorf("got batches %v, want %v", batches, want)
+	}
+}
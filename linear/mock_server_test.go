@@ -0,0 +1,563 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/steampipe-plugin-sdk/v5/anywhere"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mockSchema is the bundled Linear schema which every request sent to the
+// mock server is validated against, as the real API would
+var mockSchema *ast.Schema
+
+func TestMain(m *testing.M) {
+	schema, err := os.ReadFile(filepath.Join("..", "gql", "schema.graphql"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "reading schema:", err)
+		os.Exit(1)
+	}
+	mockSchema, err = gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(schema)})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loading schema:", err)
+		os.Exit(1)
+	}
+
+	// the memoized connection relies on the connection cache, which is only
+	// set up when the SDK serves the plugin
+	connectCached = connectUncached
+	getOrganizationIdMemoized = getOrganizationIdUncached
+
+	os.Exit(m.Run())
+}
+
+// graphqlRequest is a request received by the mock server.
+type graphqlRequest struct {
+	OperationName string                 `json:"operationName"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	Authorization string                 `json:"-"`
+}
+
+// mockLinear is an in-process stand-in for the Linear GraphQL API. It answers
+// each operation from testdata/<operationName>.json, which maps the value of
+// the `after` variable to the response for that page ("" for the first page).
+type mockLinear struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []graphqlRequest
+	// statuses makes an operation fail with the given HTTP status code
+	statuses map[string]int
+	// errors makes an operation return the given GraphQL errors
+	errors map[string][]map[string]interface{}
+	// headers are added to every response
+	headers http.Header
+	// complexity is the complexity of each node of an operation's page
+	complexity map[string]int
+	// authorization is the only Authorization header accepted, when set
+	authorization string
+	// fixtures are the directories of the fixtures answering the requests
+	// with an Authorization header, before testdata
+	fixtures map[string]string
+	// schema is the schema requests are validated against
+	schema *ast.Schema
+}
+
+func newMockLinear(t *testing.T) *mockLinear {
+	t.Helper()
+	m := &mockLinear{
+		t:          t,
+		statuses:   map[string]int{},
+		errors:     map[string][]map[string]interface{}{},
+		headers:    http.Header{},
+		complexity: map[string]int{},
+		fixtures:   map[string]string{},
+		schema:     mockSchema,
+	}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockLinear) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req graphqlRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Authorization = r.Header.Get("Authorization")
+
+	m.mu.Lock()
+	m.requests = append(m.requests, req)
+	status, failed := m.statuses[req.OperationName]
+	gqlErrors, errored := m.errors[req.OperationName]
+	for key, values := range m.headers {
+		w.Header()[key] = values
+	}
+	perNode := m.complexity[req.OperationName]
+	authorization := m.authorization
+	fixtures := m.fixtures[req.Authorization]
+	schema := m.schema
+	m.mu.Unlock()
+
+	if authorization != "" && req.Authorization != authorization {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+			"errors": []map[string]interface{}{{"message": "Authentication required", "extensions": map[string]interface{}{"code": "AUTHENTICATION_ERROR"}}},
+		})
+		return
+	}
+
+	if failed {
+		writeJSON(w, status, map[string]interface{}{
+			"errors": []map[string]interface{}{{"message": http.StatusText(status)}},
+		})
+		return
+	}
+	if errored {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": nil, "errors": gqlErrors})
+		return
+	}
+
+	if _, errs := gqlparser.LoadQuery(schema, req.Query); len(errs) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": errs})
+		return
+	}
+
+	if perNode > 0 {
+		first, _ := req.Variables["first"].(float64)
+		complexity := int(first) * perNode
+		if complexity > maxQueryComplexity {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"errors": []map[string]interface{}{{
+					"message":    fmt.Sprintf("Query too complex - complexity is %d, maximum allowed is %d", complexity, maxQueryComplexity),
+					"extensions": map[string]interface{}{"code": "QUERY_TOO_COMPLEX"},
+				}},
+			})
+			return
+		}
+		w.Header().Set(headerComplexity, fmt.Sprint(complexity))
+	}
+
+	fixture, err := os.ReadFile(filepath.Join(fixtures, req.OperationName+".json"))
+	if fixtures == "" || errors.Is(err, fs.ErrNotExist) {
+		fixture, err = os.ReadFile(filepath.Join("testdata", req.OperationName+".json"))
+	}
+	if err != nil {
+		m.t.Errorf("no fixture for operation %s: %v", req.OperationName, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var pages map[string]json.RawMessage
+	if err := json.Unmarshal(fixture, &pages); err != nil {
+		m.t.Errorf("invalid fixture for operation %s: %v", req.OperationName, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	after, _ := req.Variables["after"].(string)
+	page, ok := pages[after]
+	if !ok {
+		m.t.Errorf("no page %q in fixture for operation %s", after, req.OperationName)
+		http.Error(w, "unknown cursor", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]json.RawMessage{"data": page})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Requests returns the requests received so far.
+func (m *mockLinear) Requests() []graphqlRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]graphqlRequest(nil), m.requests...)
+}
+
+// Operations returns the names of the operations received so far, in order.
+func (m *mockLinear) Operations() []string {
+	var ops []string
+	for _, req := range m.Requests() {
+		ops = append(ops, req.OperationName)
+	}
+	return ops
+}
+
+// FailWith makes every request for the operation fail with the HTTP status.
+func (m *mockLinear) FailWith(operation string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses[operation] = status
+}
+
+// ErrorWith makes every request for the operation return a GraphQL error
+// with the given message and extensions.
+func (m *mockLinear) ErrorWith(operation, message string, extensions map[string]interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[operation] = []map[string]interface{}{{"message": message, "extensions": extensions}}
+}
+
+// SetHeader adds the header to every response.
+func (m *mockLinear) SetHeader(key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.headers.Set(key, value)
+}
+
+// Authorize makes the server reject requests without the Authorization header.
+func (m *mockLinear) Authorize(authorization string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authorization = authorization
+}
+
+// FixturesFor answers the requests with the Authorization header from the
+// fixtures in dir, falling back to testdata for the operations it lacks.
+func (m *mockLinear) FixturesFor(authorization, dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fixtures[authorization] = dir
+}
+
+// SetComplexity makes each node of the operation's pages cost perNode, and
+// rejects pages costing more than the maximum query complexity.
+func (m *mockLinear) SetComplexity(operation string, perNode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.complexity[operation] = perNode
+}
+
+// RemoveField removes the field of the type from the schema the requests are
+// validated against, as if Linear had removed it from the API.
+func (m *mockLinear) RemoveField(typeName, field string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	source, err := os.ReadFile(filepath.Join("..", "gql", "schema.graphql"))
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	if m.schema == mockSchema {
+		if m.schema, err = gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(source)}); err != nil {
+			m.t.Fatal(err)
+		}
+	}
+	def := m.schema.Types[typeName]
+	def.Fields = slices.DeleteFunc(def.Fields, func(f *ast.FieldDefinition) bool { return f.Name == field })
+}
+
+//// QUERY DATA
+
+// testContext returns a context carrying the logger which plugin.Logger expects.
+func testContext() context.Context {
+	return context.WithValue(context.Background(), context_key.Logger, hclog.NewNullLogger())
+}
+
+// testQual is a single qual applied to a test query.
+type testQual struct {
+	column   string
+	operator string
+	value    *proto.QualValue
+}
+
+func stringQual(column, value string) testQual {
+	return testQual{column, "=", &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: value}}}
+}
+
+func listQual(column, operator string, values ...string) testQual {
+	list := &proto.QualValueList{}
+	for _, value := range values {
+		list.Values = append(list.Values, &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: value}})
+	}
+	return testQual{column, operator, &proto.QualValue{Value: &proto.QualValue_ListValue{ListValue: list}}}
+}
+
+func nullQual(column, operator string) testQual {
+	return testQual{column, operator, nil}
+}
+
+func boolQual(column string, value bool) testQual {
+	return testQual{column, "=", &proto.QualValue{Value: &proto.QualValue_BoolValue{BoolValue: value}}}
+}
+
+func doubleQual(column, operator string, value float64) testQual {
+	return testQual{column, operator, &proto.QualValue{Value: &proto.QualValue_DoubleValue{DoubleValue: value}}}
+}
+
+func timeQual(column, operator string, value time.Time) testQual {
+	return testQual{column, operator, &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(value)}}}
+}
+
+// testQuery is a QueryData wired to the mock server which collects the
+// streamed rows.
+type testQuery struct {
+	*plugin.QueryData
+	mu   sync.Mutex
+	rows []interface{}
+}
+
+func newTestQuery(m *mockLinear, config linearConfig, qualList ...testQual) *testQuery {
+	return newLimitedTestQuery(m, config, nil, qualList...)
+}
+
+// newLimitedTestQuery returns a test query with the limit of a `limit` clause,
+// which RowsRemaining starts from.
+func newLimitedTestQuery(m *mockLinear, config linearConfig, limit *int64, qualList ...testQual) *testQuery {
+	token := "lin_api_test"
+	if config.Token == nil && len(config.Workspaces) == 0 {
+		config.Token = &token
+	}
+	if config.Endpoint == nil {
+		config.Endpoint = &m.server.URL
+	}
+
+	// each test has its own connection, and so its own rate limiter
+	q := &testQuery{}
+	d := probeQueryData(m.t, limit)
+	d.Connection = &plugin.Connection{Name: m.t.Name(), Config: config}
+	d.ConnectionCache = nil
+	// select every column, as the queries of the tests name no columns
+	d.QueryContext.Columns = nil
+	d.EqualsQuals = map[string]*proto.QualValue{}
+	d.Quals = plugin.KeyColumnQualMap{}
+	d.StreamListItem = func(_ context.Context, items ...interface{}) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.rows = append(q.rows, items...)
+	}
+	for _, tq := range qualList {
+		if tq.operator == "=" {
+			d.EqualsQuals[tq.column] = tq.value
+		}
+		if d.Quals[tq.column] == nil {
+			d.Quals[tq.column] = &plugin.KeyColumnQuals{Name: tq.column}
+		}
+		d.Quals[tq.column].Quals = append(d.Quals[tq.column].Quals, &quals.Qual{Column: tq.column, Operator: tq.operator, Value: tq.value})
+	}
+
+	q.QueryData = d
+	return q
+}
+
+// probeServer runs the probe table in process. The SDK only sets up the row
+// count behind RowsRemaining when it builds a QueryData itself, so test
+// queries start from the QueryData of a scan of the probe table.
+var probeServer = sync.OnceValues(func() (*grpc.PluginServer, error) {
+	probe := &plugin.Plugin{
+		Name: "linear_probe",
+		TableMap: map[string]*plugin.Table{
+			"probe": {
+				Name: "probe",
+				List: &plugin.ListConfig{
+					Hydrate: func(_ context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+						probed <- d
+						return nil, nil
+					},
+				},
+				Columns: []*plugin.Column{{Name: "id", Type: proto.ColumnType_STRING}},
+			},
+		},
+	}
+	server := plugin.Server(&plugin.ServeOpts{PluginFunc: func(context.Context) *plugin.Plugin { return probe }})
+	_, err := server.SetAllConnectionConfigs(&proto.SetAllConnectionConfigsRequest{
+		Configs:        []*proto.ConnectionConfig{{Connection: "probe", Plugin: probe.Name}},
+		MaxCacheSizeMb: 16,
+	})
+	return server, err
+})
+
+// probed receives the QueryData of each scan of the probe table
+var probed = make(chan *plugin.QueryData, 1)
+
+// probeMu serializes the scans of the probe table
+var probeMu sync.Mutex
+
+// probeQueryData returns the QueryData of a scan of the probe table with the
+// limit.
+func probeQueryData(t *testing.T, limit *int64) *plugin.QueryData {
+	t.Helper()
+	server, err := probeServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	probeMu.Lock()
+	defer probeMu.Unlock()
+
+	var nullableLimit *proto.NullableInt
+	if limit != nil {
+		nullableLimit = &proto.NullableInt{Value: *limit}
+	}
+	stream := anywhere.NewLocalPluginStream(context.Background())
+	server.CallExecuteAsync(&proto.ExecuteRequest{
+		Table:        "probe",
+		QueryContext: &proto.QueryContext{Columns: []string{"id"}, Limit: nullableLimit},
+		CallId:       t.Name(),
+		Connection:   "probe",
+		ExecuteConnectionData: map[string]*proto.ExecuteConnectionData{
+			"probe": {Limit: nullableLimit},
+		},
+	}, stream)
+	for {
+		row, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row == nil {
+			break
+		}
+	}
+	return <-probed
+}
+
+// newPluginServer runs the plugin in process with a single connection named
+// linear, configured with the HCL config.
+func newPluginServer(t *testing.T, config string) *grpc.PluginServer {
+	t.Helper()
+	server := plugin.Server(&plugin.ServeOpts{PluginFunc: Plugin})
+	if _, err := server.SetAllConnectionConfigs(&proto.SetAllConnectionConfigsRequest{
+		Configs:        []*proto.ConnectionConfig{{Connection: "linear", Plugin: "linear", Config: config}},
+		MaxCacheSizeMb: 16,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+// executeQuery scans the table through the plugin server with the query cache
+// enabled, as Steampipe does, and returns the string values of the columns of
+// each row.
+func executeQuery(t *testing.T, server *grpc.PluginServer, callId string, table string, columns []string, equalsQuals map[string]string) []map[string]string {
+	t.Helper()
+	qualMap := map[string]*proto.Quals{}
+	for column, value := range equalsQuals {
+		qualMap[column] = &proto.Quals{Quals: []*proto.Qual{{
+			FieldName: column,
+			Operator:  &proto.Qual_StringValue{StringValue: "="},
+			Value:     &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: value}},
+		}}}
+	}
+	stream := anywhere.NewLocalPluginStream(context.Background())
+	server.CallExecuteAsync(&proto.ExecuteRequest{
+		Table:        table,
+		QueryContext: &proto.QueryContext{Columns: columns, Quals: qualMap},
+		CallId:       callId,
+		Connection:   "linear",
+		ExecuteConnectionData: map[string]*proto.ExecuteConnectionData{
+			"linear": {CacheEnabled: true, CacheTtl: 300},
+		},
+	}, stream)
+
+	var rows []map[string]string
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response == nil {
+			return rows
+		}
+		row := map[string]string{}
+		for _, column := range columns {
+			row[column] = response.Row.Columns[column].GetStringValue()
+		}
+		rows = append(rows, row)
+	}
+}
+
+// columnValues returns the values of the columns of the table for each row,
+// as read by the column transforms.
+func columnValues(t *testing.T, table *plugin.Table, rows []interface{}, columns ...string) []map[string]interface{} {
+	t.Helper()
+	var values []map[string]interface{}
+	for _, row := range rows {
+		rowValues := map[string]interface{}{}
+		for _, column := range table.Columns {
+			if !slices.Contains(columns, column.Name) {
+				continue
+			}
+			transforms := column.Transform
+			if transforms == nil {
+				transforms = transform.FromCamel()
+			}
+			value, err := transforms.Execute(context.Background(), &transform.TransformData{HydrateItem: row, ColumnName: column.Name})
+			if err != nil {
+				t.Fatalf("column %s: %v", column.Name, err)
+			}
+			rowValues[column.Name] = value
+		}
+		values = append(values, rowValues)
+	}
+	return values
+}
+
+// assertJSON compares the JSON encoding of got with the expected JSON
+// document, ignoring null fields.
+func assertJSON(t *testing.T, got interface{}, want string) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshalling %v: %v", got, err)
+	}
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(gotJSON, &gotValue); err != nil {
+		t.Fatal(err)
+	}
+	gotValue = dropNulls(gotValue)
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		gotJSON, _ = json.Marshal(gotValue)
+		t.Errorf("got %s, want %s", gotJSON, want)
+	}
+}
+
+func dropNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if item == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = dropNulls(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = dropNulls(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,147 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultOAuthTokenURL is the Linear OAuth token endpoint used when no token
+// URL is configured
+const defaultOAuthTokenURL = "https://api.linear.app/oauth/token"
+
+// oauthExpiryLeeway refreshes access tokens shortly before they expire
+const oauthExpiryLeeway = time.Minute
+
+// oauthTokenSource obtains OAuth access tokens for a connection, with the
+// refresh token grant when a refresh token is configured and the client
+// credentials grant otherwise.
+type oauthTokenSource struct {
+	client       *http.Client
+	clientID     string
+	clientSecret string
+	tokenURL     string
+
+	mu           sync.Mutex
+	refreshToken string
+	accessToken  string
+	expiry       time.Time
+}
+
+// oauthTokenSources holds the token source of each OAuth client, so that
+// access tokens are reused across the connection's queries.
+var oauthTokenSources sync.Map
+
+type oauthTokenSourceKey struct {
+	connection, clientID, clientSecret, refreshToken, tokenURL string
+}
+
+func getOAuthTokenSource(connectionName string, client *http.Client, clientID, clientSecret, refreshToken, tokenURL string) *oauthTokenSource {
+	key := oauthTokenSourceKey{connectionName, clientID, clientSecret, refreshToken, tokenURL}
+	source, _ := oauthTokenSources.LoadOrStore(key, &oauthTokenSource{
+		client:       client,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		refreshToken: refreshToken,
+		tokenURL:     tokenURL,
+	})
+	return source.(*oauthTokenSource)
+}
+
+func (s *oauthTokenSource) authorization(ctx context.Context) (string, error) {
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + accessToken, nil
+}
+
+// token returns a valid access token, requesting a new one when there is
+// none or it is about to expire.
+func (s *oauthTokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && (s.expiry.IsZero() || time.Now().Add(oauthExpiryLeeway).Before(s.expiry)) {
+		return s.accessToken, nil
+	}
+	if err := s.fetch(ctx); err != nil {
+		return "", err
+	}
+	return s.accessToken, nil
+}
+
+// invalidate discards the access token after the API rejected it, unless it
+// has already been replaced.
+func (s *oauthTokenSource) invalidate(authorization string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if "Bearer "+s.accessToken == authorization {
+		s.accessToken = ""
+	}
+	return true
+}
+
+// fetch requests a new access token. s.mu must be held.
+func (s *oauthTokenSource) fetch(ctx context.Context) error {
+	form := url.Values{
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+	}
+	if s.refreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", s.refreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+		form.Set("scope", "read")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("requesting an OAuth access token returned error %v: %s", resp.Status, body)
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return fmt.Errorf("decoding the OAuth access token: %v", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("the OAuth token endpoint returned no access token")
+	}
+
+	s.accessToken = token.AccessToken
+	s.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	// refresh tokens may be rotated on use. The rotated token is only kept in
+	// memory, so the configured one is used again once the plugin restarts.
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package linear
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// mockOAuth is a stand-in for the Linear OAuth token endpoint, which issues
+// access-1, access-2, ... and rotates the refresh token on each grant.
+type mockOAuth struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	grants []url.Values
+}
+
+func newMockOAuth(t *testing.T) *mockOAuth {
+	t.Helper()
+	o := &mockOAuth{}
+	o.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+		o.mu.Lock()
+		o.grants = append(o.grants, r.PostForm)
+		n := len(o.grants)
+		o.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token":  fmt.Sprintf("access-%d", n),
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"refresh_token": fmt.Sprintf("refresh-%d", n+1),
+		})
+	}))
+	t.Cleanup(o.server.Close)
+	return o
+}
+
+func (o *mockOAuth) Grants() []url.Values {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]url.Values(nil), o.grants...)
+}
+
+func (o *mockOAuth) config(refreshToken string) linearConfig {
+	config := linearConfig{
+		ClientID:      ptr("client"),
+		ClientSecret:  ptr("secret"),
+		OAuthTokenURL: ptr(o.server.URL),
+	}
+	if refreshToken != "" {
+		config.RefreshToken = ptr(refreshToken)
+	}
+	return config
+}
+
+func TestOAuthRefreshToken(t *testing.T) {
+	m := newMockLinear(t)
+	m.Authorize("Bearer access-1")
+	o := newMockOAuth(t)
+
+	for i := 0; i < 2; i++ {
+		q := newTestQuery(m, o.config("refresh-1"))
+		if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	grants := o.Grants()
+	if len(grants) != 1 {
+		t.Fatalf("got %d grants, want the access token to be reused", len(grants))
+	}
+	if grants[0].Get("grant_type") != "refresh_token" || grants[0].Get("refresh_token") != "refresh-1" {
+		t.Errorf("got grant %v, want a refresh_token grant with refresh-1", grants[0])
+	}
+}
+
+func TestOAuthRefreshesOnUnauthorized(t *testing.T) {
+	m := newMockLinear(t)
+	m.Authorize("Bearer access-2")
+	o := newMockOAuth(t)
+	q := newTestQuery(m, o.config("refresh-1"))
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(q.rows))
+	}
+
+	var authorizations []string
+	for _, req := range m.Requests() {
+		authorizations = append(authorizations, req.Authorization)
+	}
+	if len(authorizations) != 2 || authorizations[0] != "Bearer access-1" || authorizations[1] != "Bearer access-2" {
+		t.Errorf("got authorizations %v, want access-1 then access-2", authorizations)
+	}
+	grants := o.Grants()
+	if len(grants) != 2 || grants[1].Get("refresh_token") != "refresh-2" {
+		t.Errorf("got grants %v, want the second to use the rotated refresh-2", grants)
+	}
+}
+
+func TestOAuthClientSecretChange(t *testing.T) {
+	m := newMockLinear(t)
+	m.Authorize("Bearer access-1")
+	o := newMockOAuth(t)
+
+	config := o.config("")
+	config.ClientSecret = ptr("wrong")
+	q := newTestQuery(m, config)
+	if _, err := listTeams(testContext(), q.QueryData, nil); err == nil {
+		t.Fatal("expected an error with the wrong client_secret")
+	}
+
+	// the corrected secret is used rather than the token source of the old one
+	q = newTestQuery(m, o.config(""))
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(q.rows))
+	}
+}
+
+func TestOAuthClientCredentials(t *testing.T) {
+	m := newMockLinear(t)
+	m.Authorize("Bearer access-1")
+	o := newMockOAuth(t)
+	q := newTestQuery(m, o.config(""))
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	grants := o.Grants()
+	if len(grants) != 1 || grants[0].Get("grant_type") != "client_credentials" {
+		t.Errorf("got grants %v, want a client_credentials grant", grants)
+	}
+}
+
+func TestOAuthRequiresClientSecret(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{ClientID: ptr("client")})
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err == nil {
+		t.Error("expected an error without client_secret")
+	}
+	if ops := m.Operations(); len(ops) != 0 {
+		t.Errorf("got operations %v, want none", ops)
+	}
+}
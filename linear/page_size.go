@@ -0,0 +1,164 @@
+package linear
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Linear rejects any single query whose complexity exceeds this, and reports
+// the complexity of each query it answers in the X-Complexity header.
+// https://developers.linear.app/docs/graphql/working-with-the-graphql-api/rate-limiting
+const (
+	maxQueryComplexity = 10000
+	headerComplexity   = "X-Complexity"
+	// complexityHeadroom is the share of the maximum complexity a page aims for
+	complexityHeadroom = 0.8
+)
+
+// pageSizer learns a safe page size for each operation of a connection.
+type pageSizer struct {
+	mu sync.Mutex
+	// sizes is the largest safe value of `first`, by operation name
+	sizes map[string]int
+}
+
+// pageSizers holds the page sizer of each connection, by connection name.
+var pageSizers sync.Map
+
+func getPageSizer(connectionName string) *pageSizer {
+	sizer, _ := pageSizers.LoadOrStore(connectionName, &pageSizer{sizes: map[string]int{}})
+	return sizer.(*pageSizer)
+}
+
+// pageSize returns the page size to request for the operation.
+func (p *pageSizer) pageSize(operation string, requested int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if size, ok := p.sizes[operation]; ok && size < requested {
+		return size
+	}
+	return requested
+}
+
+// learn records the complexity Linear reported for a page of the given size.
+func (p *pageSizer) learn(operation string, first int, complexity int) {
+	if first <= 0 || complexity <= 0 {
+		return
+	}
+	perNode := float64(complexity) / float64(first)
+	safe := int(maxQueryComplexity * complexityHeadroom / perNode)
+	if safe < 1 {
+		safe = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sizes[operation] = safe
+}
+
+// shrink halves the page size of the operation after a complexity error,
+// returning false once a single node is already too complex.
+func (p *pageSizer) shrink(operation string, first int) (int, bool) {
+	if first <= 1 {
+		return first, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sizes[operation] = first / 2
+	return first / 2, true
+}
+
+// pageSizeTransport caps the `first` variable of paginated queries at the page
+// size learned for the operation, and halves it and retries when Linear
+// rejects a page as too complex. The cursors returned in pageInfo keep
+// pagination correct whatever size each page ends up being.
+type pageSizeTransport struct {
+	sizer   *pageSizer
+	wrapped http.RoundTripper
+}
+
+// graphqlRequestBody is the subset of a GraphQL request read by the transport;
+// the rest of the body is kept as is.
+type graphqlRequestBody struct {
+	OperationName string                     `json:"operationName"`
+	Variables     map[string]json.RawMessage `json:"variables"`
+}
+
+func (t *pageSizeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.wrapped.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	var request graphqlRequestBody
+	if json.Unmarshal(body, &fields) != nil || json.Unmarshal(body, &request) != nil {
+		return t.wrapped.RoundTrip(withBody(req, body))
+	}
+	first, err := strconv.Atoi(string(request.Variables["first"]))
+	if err != nil || first <= 0 {
+		// not a paginated query
+		return t.wrapped.RoundTrip(withBody(req, body))
+	}
+
+	first = t.sizer.pageSize(request.OperationName, first)
+	for {
+		request.Variables["first"] = json.RawMessage(strconv.Itoa(first))
+		variables, err := json.Marshal(request.Variables)
+		if err != nil {
+			return nil, err
+		}
+		fields["variables"] = variables
+		pageBody, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.wrapped.RoundTrip(withBody(req, pageBody))
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		var response struct {
+			Errors gqlerror.List `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &response) == nil && isComplexityError(response.Errors) {
+			var ok bool
+			if first, ok = t.sizer.shrink(request.OperationName, first); ok {
+				continue
+			}
+			return resp, nil
+		}
+
+		if complexity, err := strconv.Atoi(resp.Header.Get(headerComplexity)); err == nil {
+			t.sizer.learn(request.OperationName, first, complexity)
+		}
+		return resp, nil
+	}
+}
+
+// withBody returns a copy of the request sending the given body.
+func withBody(req *http.Request, body []byte) *http.Request {
+	r := req.Clone(req.Context())
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return r
+}
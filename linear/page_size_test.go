@@ -0,0 +1,81 @@
+package linear
+
+import (
+	"testing"
+)
+
+func TestPageSizerLearn(t *testing.T) {
+	sizer := &pageSizer{sizes: map[string]int{}}
+
+	if got := sizer.pageSize("listIssues", 50); got != 50 {
+		t.Errorf("got %d before learning, want the requested 50", got)
+	}
+
+	// 50 nodes costing 10000 is 200 per node, so 40 nodes fit the headroom
+	sizer.learn("listIssues", 50, 10000)
+	if got := sizer.pageSize("listIssues", 50); got != 40 {
+		t.Errorf("got %d, want 40", got)
+	}
+	if got := sizer.pageSize("listIssues", 10); got != 10 {
+		t.Errorf("got %d, want the smaller requested 10", got)
+	}
+	if got := sizer.pageSize("listTeams", 50); got != 50 {
+		t.Errorf("got %d for another operation, want 50", got)
+	}
+}
+
+func TestPageSizerShrink(t *testing.T) {
+	sizer := &pageSizer{sizes: map[string]int{}}
+
+	if first, ok := sizer.shrink("listIssues", 50); !ok || first != 25 {
+		t.Errorf("got %d, %v, want 25, true", first, ok)
+	}
+	if got := sizer.pageSize("listIssues", 50); got != 25 {
+		t.Errorf("got %d after shrinking, want 25", got)
+	}
+	if _, ok := sizer.shrink("listIssues", 1); ok {
+		t.Error("shrank a single node page")
+	}
+}
+
+func TestListHalvesPageSizeOnComplexityError(t *testing.T) {
+	m := newMockLinear(t)
+	// pages of more than 20 teams are too complex
+	m.SetComplexity("listTeams", 500)
+	pageSize := int64(100)
+	q := newTestQuery(m, linearConfig{PageSize: &pageSize})
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(q.rows))
+	}
+	var firsts []float64
+	for _, req := range m.Requests() {
+		firsts = append(firsts, req.Variables["first"].(float64))
+	}
+	if len(firsts) != 4 || firsts[3] != 12 {
+		t.Errorf("got first %v, want 100, 50, 25 and 12", firsts)
+	}
+
+	// the next query starts from the size learned from the complexity header
+	q = newTestQuery(m, linearConfig{PageSize: &pageSize})
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Requests()[4].Variables["first"]; got != float64(16) {
+		t.Errorf("got first %v, want the learned 16", got)
+	}
+}
+
+func TestListFailsWhenSingleNodeTooComplex(t *testing.T) {
+	m := newMockLinear(t)
+	m.SetComplexity("listTeams", maxQueryComplexity+1)
+	q := newTestQuery(m, linearConfig{})
+
+	_, err := listTeams(testContext(), q.QueryData, nil)
+	if !isComplexityError(err) {
+		t.Fatalf("got %v, want a complexity error", err)
+	}
+}
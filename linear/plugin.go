@@ -12,24 +12,19 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 		Name:             "steampipe-plugin-linear",
 		DefaultTransform: transform.FromCamel(),
 		DefaultIgnoreConfig: &plugin.IgnoreConfig{
-			ShouldIgnoreErrorFunc: shouldIgnoreErrors([]string{"404"}),
+			ShouldIgnoreErrorFunc: shouldIgnoreErrors([]errorCode{errorCodeEntityNotFound}),
 		},
 		DefaultRetryConfig: &plugin.RetryConfig{
-			ShouldRetryErrorFunc: shouldRetryError([]string{"429"})},
+			ShouldRetryErrorFunc: shouldRetryError([]errorCode{errorCodeRateLimited})},
 		ConnectionConfigSchema: &plugin.ConnectionConfigSchema{
 			NewInstance: ConfigInstance,
 		},
-		ConnectionKeyColumns: []plugin.ConnectionKeyColumn{
-			{
-				Name:    "organization_id",
-				Hydrate: getOrganizationId,
-			},
-		},
 		TableMap: map[string]*plugin.Table{
 			"linear_attachment":      tableLinearAttachment(ctx),
 			"linear_comment":         tableLinearComment(ctx),
 			"linear_integration":     tableLinearIntegration(ctx),
 			"linear_issue":           tableLinearIssue(ctx),
+			"linear_issue_changes":   tableLinearIssueChanges(ctx),
 			"linear_issue_label":     tableLinearIssueLabel(ctx),
 			"linear_organization":    tableLinearOrganization(ctx),
 			"linear_project":         tableLinearProject(ctx),
@@ -38,5 +33,8 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"linear_user":            tableLinearUser(ctx),
 		},
 	}
+	for _, table := range p.TableMap {
+		addOrganizationKeyColumn(table)
+	}
 	return p
 }
@@ -0,0 +1,168 @@
+package linear
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Linear reports the remaining request and complexity budgets of the API key
+// on every response. Reset times are UTC epoch milliseconds.
+// https://developers.linear.app/docs/graphql/working-with-the-graphql-api/rate-limiting
+const (
+	headerRequestsLimit       = "X-RateLimit-Requests-Limit"
+	headerRequestsRemaining   = "X-RateLimit-Requests-Remaining"
+	headerRequestsReset       = "X-RateLimit-Requests-Reset"
+	headerComplexityLimit     = "X-RateLimit-Complexity-Limit"
+	headerComplexityRemaining = "X-RateLimit-Complexity-Remaining"
+	headerComplexityReset     = "X-RateLimit-Complexity-Reset"
+)
+
+const (
+	// throttleFraction is the share of a budget below which requests are
+	// spread out over the time left until the budget resets
+	throttleFraction = 0.1
+	// maxRateLimitWait bounds a single wait, in case of a bogus reset time
+	maxRateLimitWait = 5 * time.Minute
+	// defaultRateLimitWait is used when a 429 response carries no reset time
+	defaultRateLimitWait = 10 * time.Second
+)
+
+// rateLimitBudget is the last known state of one of the rate limits.
+type rateLimitBudget struct {
+	limit     int64
+	remaining int64
+	reset     time.Time
+}
+
+// delay returns how long to wait before spending more of the budget.
+func (b *rateLimitBudget) delay(now time.Time) time.Duration {
+	if b.reset.IsZero() || !b.reset.After(now) {
+		return 0
+	}
+	untilReset := b.reset.Sub(now)
+	if b.remaining <= 0 {
+		return untilReset
+	}
+	if float64(b.remaining) < float64(b.limit)*throttleFraction {
+		return untilReset / time.Duration(b.remaining+1)
+	}
+	return 0
+}
+
+// rateLimiter tracks the rate limits of a connection, shared by every table
+// querying through it.
+type rateLimiter struct {
+	mu         sync.Mutex
+	requests   rateLimitBudget
+	complexity rateLimitBudget
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		now:   time.Now,
+		sleep: sleepContext,
+	}
+}
+
+// rateLimiters holds the rate limiter of each connection, by connection name,
+// so that the budget outlives the cached client.
+var rateLimiters sync.Map
+
+func getRateLimiter(connectionName string) *rateLimiter {
+	limiter, _ := rateLimiters.LoadOrStore(connectionName, newRateLimiter())
+	return limiter.(*rateLimiter)
+}
+
+// delay returns how long to wait before the next request.
+func (l *rateLimiter) delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	wait := l.requests.delay(now)
+	if d := l.complexity.delay(now); d > wait {
+		wait = d
+	}
+	if wait > maxRateLimitWait {
+		wait = maxRateLimitWait
+	}
+	return wait
+}
+
+// update records the rate limit headers of a response.
+func (l *rateLimiter) update(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	updateBudget(&l.requests, resp.Header, headerRequestsLimit, headerRequestsRemaining, headerRequestsReset)
+	updateBudget(&l.complexity, resp.Header, headerComplexityLimit, headerComplexityRemaining, headerComplexityReset)
+
+	// a 429 means the budget is spent, whatever the headers said
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.requests.remaining = 0
+		if !l.requests.reset.After(l.now()) {
+			l.requests.reset = l.now().Add(retryAfter(resp.Header))
+		}
+	}
+}
+
+func updateBudget(b *rateLimitBudget, header http.Header, limitKey, remainingKey, resetKey string) {
+	remaining, err := strconv.ParseInt(header.Get(remainingKey), 10, 64)
+	if err != nil {
+		return
+	}
+	b.remaining = remaining
+	if limit, err := strconv.ParseInt(header.Get(limitKey), 10, 64); err == nil {
+		b.limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get(resetKey), 10, 64); err == nil {
+		b.reset = time.UnixMilli(reset)
+	}
+}
+
+// retryAfter returns the wait requested by a Retry-After header in seconds.
+func retryAfter(header http.Header) time.Duration {
+	if seconds, err := strconv.ParseInt(header.Get("Retry-After"), 10, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultRateLimitWait
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitTransport waits for the rate limits of the connection before each
+// request and records them from each response.
+type rateLimitTransport struct {
+	limiter *rateLimiter
+	wrapped http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := t.limiter.delay(); wait > 0 {
+		if err := t.limiter.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.wrapped.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.limiter.update(resp)
+	return resp, nil
+}
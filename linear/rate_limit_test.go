@@ -0,0 +1,92 @@
+package linear
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimitBudgetDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reset := now.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		budget rateLimitBudget
+		want   time.Duration
+	}{
+		{"unknown", rateLimitBudget{}, 0},
+		{"plenty left", rateLimitBudget{limit: 1500, remaining: 1000, reset: reset}, 0},
+		{"low", rateLimitBudget{limit: 1500, remaining: 5, reset: reset}, 10 * time.Second},
+		{"exhausted", rateLimitBudget{limit: 1500, remaining: 0, reset: reset}, time.Minute},
+		{"exhausted and reset", rateLimitBudget{limit: 1500, remaining: 0, reset: now.Add(-time.Second)}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.budget.delay(now); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter()
+	limiter.now = func() time.Time { return now }
+
+	header := http.Header{}
+	header.Set(headerRequestsLimit, "1500")
+	header.Set(headerRequestsRemaining, "1000")
+	header.Set(headerRequestsReset, strconv.FormatInt(now.Add(time.Hour).UnixMilli(), 10))
+	header.Set(headerComplexityLimit, "250000")
+	header.Set(headerComplexityRemaining, "0")
+	header.Set(headerComplexityReset, strconv.FormatInt(now.Add(30*time.Second).UnixMilli(), 10))
+	limiter.update(&http.Response{StatusCode: http.StatusOK, Header: header})
+
+	if got := limiter.delay(); got != 30*time.Second {
+		t.Errorf("got delay %v, want the complexity reset of 30s", got)
+	}
+
+	// a 429 without headers falls back to Retry-After
+	limiter = newRateLimiter()
+	limiter.now = func() time.Time { return now }
+	limiter.update(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}})
+	if got := limiter.delay(); got != 3*time.Second {
+		t.Errorf("got delay %v, want the Retry-After of 3s", got)
+	}
+}
+
+func TestRateLimitSharedAcrossTables(t *testing.T) {
+	m := newMockLinear(t)
+	m.SetHeader(headerRequestsLimit, "1500")
+	m.SetHeader(headerRequestsRemaining, "0")
+	m.SetHeader(headerRequestsReset, strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10))
+
+	var waits []time.Duration
+	limiter := getRateLimiter(t.Name())
+	limiter.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	q := newTestQuery(m, linearConfig{})
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 0 {
+		t.Fatalf("waited %v before the first request", waits)
+	}
+
+	// another table on the same connection sees the exhausted budget
+	q = newTestQuery(m, linearConfig{})
+	if _, err := listUsers(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 1 || waits[0] != maxRateLimitWait {
+		t.Errorf("got waits %v, want a single wait capped at %v", waits, maxRateLimitWait)
+	}
+}
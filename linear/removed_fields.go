@@ -0,0 +1,254 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/turbot/steampipe-plugin-linear/gql"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// cannotQueryField matches the validation error of a field the API doesn't
+// have, such as a field Linear removed since the bundled schema was saved.
+var cannotQueryField = regexp.MustCompile(`^Cannot query field "(\w+)" on type "(\w+)"`)
+
+// removedFields learns the fields of each operation of a connection which the
+// API no longer has.
+type removedFields struct {
+	mu sync.Mutex
+	// paths are the paths of the removed fields from the root of the
+	// operation, such as "teams.nodes.cycleCalenderUrl", by operation name
+	paths map[string][]string
+}
+
+// removedFieldSets holds the removed fields of each connection, by connection
+// name.
+var removedFieldSets sync.Map
+
+func getRemovedFields(connectionName string) *removedFields {
+	fields, _ := removedFieldSets.LoadOrStore(connectionName, &removedFields{paths: map[string][]string{}})
+	return fields.(*removedFields)
+}
+
+// removed returns the paths of the removed fields of the operation.
+func (r *removedFields) removed(operation string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.paths[operation])
+}
+
+// learn records the removed field of the operation, returning false when it
+// was already known.
+func (r *removedFields) learn(operation, path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.Contains(r.paths[operation], path) {
+		return false
+	}
+	r.paths[operation] = append(r.paths[operation], path)
+	return true
+}
+
+// removedFieldTransport omits the fields Linear removed from the queries, so
+// a removed field reads as null in its column instead of failing the whole
+// table. When the API rejects a query with "Cannot query field" errors, the
+// fields are logged and learned for the operation, and the query is retried
+// without them.
+type removedFieldTransport struct {
+	fields  *removedFields
+	wrapped http.RoundTripper
+}
+
+func (t *removedFieldTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.wrapped.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	var request struct {
+		OperationName string `json:"operationName"`
+		Query         string `json:"query"`
+	}
+	if json.Unmarshal(body, &fields) != nil || json.Unmarshal(body, &request) != nil || request.Query == "" {
+		return t.wrapped.RoundTrip(withBody(req, body))
+	}
+
+	query := request.Query
+	for {
+		if removed := t.fields.removed(request.OperationName); len(removed) > 0 {
+			if query, err = removeFieldPaths(query, removed); err != nil {
+				return nil, err
+			}
+			if fields["query"], err = json.Marshal(query); err != nil {
+				return nil, err
+			}
+			if body, err = json.Marshal(fields); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.wrapped.RoundTrip(withBody(req, body))
+		if err != nil {
+			return nil, err
+		}
+		// the API rejects queries failing validation with an error status,
+		// so successful responses are streamed through
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		var response struct {
+			Errors gqlerror.List `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &response) != nil {
+			return resp, nil
+		}
+		learned := false
+		for _, gqlErr := range response.Errors {
+			match := cannotQueryField.FindStringSubmatch(gqlErr.Message)
+			if match == nil {
+				continue
+			}
+			for _, location := range gqlErr.Locations {
+				path := fieldPathAt(query, match[1], location.Line)
+				if path == "" || !t.fields.learn(request.OperationName, path) {
+					continue
+				}
+				plugin.Logger(req.Context()).Warn("linear.removedFieldTransport", "operation", request.OperationName, "removed_field", match[2]+"."+match[1], "path", path)
+				learned = true
+			}
+		}
+		if !learned {
+			return resp, nil
+		}
+	}
+}
+
+// loggedDeprecations holds the connections whose deprecated fields were
+// logged, by connection name.
+var loggedDeprecations sync.Map
+
+// logDeprecatedFields warns once per connection of the fields the queries
+// select which the bundled schema marks as deprecated, so they can be replaced
+// before Linear removes them.
+func logDeprecatedFields(ctx context.Context, connectionName string) {
+	if _, logged := loggedDeprecations.LoadOrStore(connectionName, true); logged {
+		return
+	}
+	fields, err := gql.DeprecatedFields()
+	if err != nil {
+		plugin.Logger(ctx).Warn("linear.logDeprecatedFields", "schema_error", err)
+		return
+	}
+	for _, field := range fields {
+		plugin.Logger(ctx).Warn("linear.logDeprecatedFields", "connection", connectionName, "deprecated_field", field.Field, "reason", field.Reason, "operations", strings.Join(field.Operations, ", "))
+	}
+}
+
+// fieldPathAt returns the path of the field with the name selected at the
+// line of the query, or "" when there is none.
+func fieldPathAt(query, name string, line int) string {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return ""
+	}
+	var find func(set ast.SelectionSet, path []string) string
+	find = func(set ast.SelectionSet, path []string) string {
+		for _, selection := range set {
+			switch s := selection.(type) {
+			case *ast.Field:
+				fieldPath := append(slices.Clone(path), responseKey(s))
+				if s.Name == name && s.Position != nil && s.Position.Line == line {
+					return strings.Join(fieldPath, ".")
+				}
+				if found := find(s.SelectionSet, fieldPath); found != "" {
+					return found
+				}
+			case *ast.InlineFragment:
+				if found := find(s.SelectionSet, path); found != "" {
+					return found
+				}
+			}
+		}
+		return ""
+	}
+	for _, operation := range doc.Operations {
+		if found := find(operation.SelectionSet, nil); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// removeFieldPaths removes the fields at the paths from the query, along with
+// the objects left without any field and the variables no longer used.
+func removeFieldPaths(query string, paths []string) (string, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return "", err
+	}
+
+	var remove func(set ast.SelectionSet, path string) ast.SelectionSet
+	remove = func(set ast.SelectionSet, path string) ast.SelectionSet {
+		var kept ast.SelectionSet
+		for _, selection := range set {
+			switch s := selection.(type) {
+			case *ast.Field:
+				fieldPath := strings.TrimPrefix(path+"."+responseKey(s), ".")
+				if slices.Contains(paths, fieldPath) {
+					continue
+				}
+				if len(s.SelectionSet) > 0 {
+					if s.SelectionSet = remove(s.SelectionSet, fieldPath); len(s.SelectionSet) == 0 {
+						continue
+					}
+				}
+			case *ast.InlineFragment:
+				if s.SelectionSet = remove(s.SelectionSet, path); len(s.SelectionSet) == 0 {
+					continue
+				}
+			}
+			kept = append(kept, selection)
+		}
+		return kept
+	}
+
+	for _, operation := range doc.Operations {
+		operation.SelectionSet = remove(operation.SelectionSet, "")
+		removeUnusedVariables(operation)
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String(), nil
+}
+
+// responseKey returns the key of the field in the response.
+func responseKey(field *ast.Field) string {
+	if field.Alias != "" {
+		return field.Alias
+	}
+	return field.Name
+}
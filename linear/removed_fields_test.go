@@ -0,0 +1,131 @@
+package linear
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-linear/gql"
+)
+
+func TestListOmitsRemovedField(t *testing.T) {
+	m := newMockLinear(t)
+	m.RemoveField("Team", "cycleCalenderUrl")
+	q := newTestQuery(m, linearConfig{})
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(q.rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(q.rows))
+	}
+	// the removed field reads as null, and is dropped from the rows
+	assertJSON(t, columnValues(t, tableLinearTeam(context.Background()), q.rows, "key", "cycle_calender_url"), `[{"key": "ENG"}]`)
+
+	requests := m.Requests()
+	if ops := m.Operations(); len(ops) != 2 {
+		t.Fatalf("got operations %v, want listTeams retried once", ops)
+	}
+	if !strings.Contains(requests[0].Query, "cycleCalenderUrl") || strings.Contains(requests[1].Query, "cycleCalenderUrl") {
+		t.Errorf("got queries %q and %q, want the retry without cycleCalenderUrl", requests[0].Query, requests[1].Query)
+	}
+
+	// later queries of the connection omit the removed field upfront
+	if _, err := listTeams(testContext(), newTestQuery(m, linearConfig{}).QueryData, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if ops := m.Operations(); len(ops) != 3 {
+		t.Errorf("got operations %v, want a single request for the second list", ops)
+	}
+}
+
+func TestSuccessfulResponsesAreNotInspected(t *testing.T) {
+	m := newMockLinear(t)
+	// a successful response is streamed through, whatever its errors
+	m.ErrorWith("listTeams", `Cannot query field "cycleCalenderUrl" on type "Team".`, nil)
+	q := newTestQuery(m, linearConfig{})
+
+	if _, err := listTeams(testContext(), q.QueryData, nil); err == nil {
+		t.Error("got no error, want the error of the response")
+	}
+	if ops := m.Operations(); len(ops) != 1 {
+		t.Errorf("got operations %v, want listTeams without a retry", ops)
+	}
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	fields, err := gql.DeprecatedFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range fields {
+		if field.Field == "IssueLabel.organization" {
+			if field.Reason != "Workspace labels are identified by their team being null." || !slices.Contains(field.Operations, "listIssueLabels") {
+				t.Errorf("got %+v, want the reason and the operations selecting it", field)
+			}
+			return
+		}
+	}
+	t.Errorf("got deprecated fields %+v, want IssueLabel.organization", fields)
+}
+
+func TestRemovedRelationFieldDropsEmptyObject(t *testing.T) {
+	m := newMockLinear(t)
+	for _, field := range []string{
+		"id", "active", "admin", "archivedAt", "avatarUrl", "calendarHash", "createdAt", "createdIssueCount",
+		"description", "disableReason", "displayName", "email", "guest", "inviteHash", "isMe", "lastSeen",
+		"name", "statusEmoji", "statusLabel", "statusUntilAt", "timezone", "updatedAt", "url",
+	} {
+		m.RemoveField("User", field)
+	}
+	q := newTestQuery(m, linearConfig{})
+	q.QueryContext.Columns = []string{"id", "creator_id"}
+
+	if _, err := listAttachments(testContext(), q.QueryData, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	requests := m.Requests()
+	last := requests[len(requests)-1].Query
+	if strings.Contains(last, "creator") {
+		t.Errorf("got query %q, want the creator left without fields omitted", last)
+	}
+}
+
+func TestRemoveFieldPaths(t *testing.T) {
+	query := `query listTeams ($first: Int, $firstIssue: Int) {
+	teams(first: $first) {
+		nodes {
+			id
+			cycleCalenderUrl
+			issues(first: $firstIssue) {
+				nodes {
+					id
+				}
+			}
+		}
+	}
+}`
+	got, err := removeFieldPaths(query, []string{"teams.nodes.cycleCalenderUrl", "teams.nodes.issues.nodes.id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `query listTeams ($first: Int) {
+	teams(first: $first) {
+		nodes {
+			id
+		}
+	}
+}
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if path := fieldPathAt(query, "cycleCalenderUrl", 5); path != "teams.nodes.cycleCalenderUrl" {
+		t.Errorf("got path %q, want teams.nodes.cycleCalenderUrl", path)
+	}
+	if path := fieldPathAt(query, "cycleCalenderUrl", 4); path != "" {
+		t.Errorf("got path %q for another line, want none", path)
+	}
+}
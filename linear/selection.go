@@ -0,0 +1,182 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/iancoleman/strcase"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// columnFields maps the columns of a table to the GraphQL fields they are
+// read from, for the columns which are not read from the field named after
+// the column in camel case.
+type columnFields map[string][]string
+
+// selectColumns returns a client which only requests the fields of the
+// entity needed for the columns selected by the query and for its quals.
+func (c *linearClient) selectColumns(d *plugin.QueryData, fields columnFields) graphql.Client {
+	keep := selectedFields(d, fields)
+	if keep == nil {
+		return c.client
+	}
+	return &selectingClient{wrapped: c.client, keep: keep}
+}
+
+// selectedFields returns the fields of the entity needed for the columns
+// selected by the query and for its quals, or nil when all columns are.
+func selectedFields(d *plugin.QueryData, fields columnFields) map[string]bool {
+	if d.QueryContext == nil || len(d.QueryContext.Columns) == 0 {
+		return nil
+	}
+
+	columns := append([]string{}, d.QueryContext.Columns...)
+	for column := range d.Quals {
+		columns = append(columns, column)
+	}
+
+	keep := map[string]bool{"id": true, "__typename": true}
+	for _, column := range columns {
+		if columnFields, ok := fields[column]; ok {
+			for _, field := range columnFields {
+				keep[field] = true
+			}
+			continue
+		}
+		keep[strcase.ToLowerCamel(column)] = true
+	}
+	return keep
+}
+
+// selectingClient prunes the entity fields of each query it sends to the
+// fields to keep.
+type selectingClient struct {
+	wrapped graphql.Client
+	keep    map[string]bool
+}
+
+func (c *selectingClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	query, err := pruneQuery(req.Query, c.keep)
+	if err != nil {
+		return err
+	}
+	pruned := *req
+	pruned.Query = query
+	return c.wrapped.MakeRequest(ctx, &pruned, resp)
+}
+
+// pruneQuery removes the fields of the queried entity which are not in keep.
+// The entity is the `nodes` of a paginated query, or the root field of a
+// get query. Variables which are no longer used are removed too.
+func pruneQuery(query string, keep map[string]bool) (string, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return "", err
+	}
+
+	for _, operation := range doc.Operations {
+		entity := entitySelection(&operation.SelectionSet)
+		*entity = pruneSelection(*entity, keep)
+		removeUnusedVariables(operation)
+	}
+
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String(), nil
+}
+
+// entitySelection walks down the single fields of the selection set to the
+// selection of the queried entity.
+func entitySelection(set *ast.SelectionSet) *ast.SelectionSet {
+	current := set
+	for {
+		var only *ast.Field
+		for _, selection := range *current {
+			field, ok := selection.(*ast.Field)
+			if !ok {
+				return current
+			}
+			if field.Name == "nodes" {
+				return &field.SelectionSet
+			}
+			only = field
+		}
+		if len(*current) != 1 || len(only.SelectionSet) == 0 {
+			return current
+		}
+		current = &only.SelectionSet
+	}
+}
+
+func pruneSelection(set ast.SelectionSet, keep map[string]bool) ast.SelectionSet {
+	var pruned ast.SelectionSet
+	for _, selection := range set {
+		field, ok := selection.(*ast.Field)
+		if !ok || keep[field.Alias] || keep[field.Name] {
+			pruned = append(pruned, selection)
+		}
+	}
+	return pruned
+}
+
+// removeUnusedVariables removes the variables of the operation which are no
+// longer used by its selection.
+func removeUnusedVariables(operation *ast.OperationDefinition) {
+	used := map[string]bool{}
+	collectVariables(operation.SelectionSet, used)
+	var variables ast.VariableDefinitionList
+	for _, variable := range operation.VariableDefinitions {
+		if used[variable.Variable] {
+			variables = append(variables, variable)
+		}
+	}
+	operation.VariableDefinitions = variables
+}
+
+func collectVariables(set ast.SelectionSet, used map[string]bool) {
+	for _, selection := range set {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+		for _, argument := range field.Arguments {
+			collectValueVariables(argument.Value, used)
+		}
+		for _, directive := range field.Directives {
+			for _, argument := range directive.Arguments {
+				collectValueVariables(argument.Value, used)
+			}
+		}
+		collectVariables(field.SelectionSet, used)
+	}
+}
+
+func collectValueVariables(value *ast.Value, used map[string]bool) {
+	if value == nil {
+		return
+	}
+	if value.Kind == ast.Variable {
+		used[value.Raw] = true
+	}
+	for _, child := range value.Children {
+		collectValueVariables(child.Value, used)
+	}
+}
+
+// columnSelected reports whether any of the columns is selected by the query.
+func columnSelected(d *plugin.QueryData, columns ...string) bool {
+	if d.QueryContext == nil || len(d.QueryContext.Columns) == 0 {
+		return true
+	}
+	for _, column := range columns {
+		if slices.Contains(d.QueryContext.Columns, column) {
+			return true
+		}
+	}
+	return false
+}
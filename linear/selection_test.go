@@ -0,0 +1,166 @@
+package linear
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/iancoleman/strcase"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestPruneQuery(t *testing.T) {
+	query := `query listThings($first: Int, $after: String, $firstChild: Int) {
+		things(first: $first, after: $after) {
+			nodes { id name children(first: $firstChild) { nodes { id } } owner { id name } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	pruned, err := pruneQuery(query, map[string]bool{"id": true, "owner": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := parser.ParseQuery(&ast.Source{Input: pruned})
+	if err != nil {
+		t.Fatalf("invalid pruned query %s: %v", pruned, err)
+	}
+	if got := fieldNames(*entitySelection(&doc.Operations[0].SelectionSet)); strings.Join(got, ",") != "id,owner" {
+		t.Errorf("got fields %v, want id and owner", got)
+	}
+	if !strings.Contains(pruned, "pageInfo") || !strings.Contains(pruned, "owner {") {
+		t.Errorf("pruned more than the entity fields: %s", pruned)
+	}
+	var variables []string
+	for _, variable := range doc.Operations[0].VariableDefinitions {
+		variables = append(variables, variable.Variable)
+	}
+	if strings.Join(variables, ",") != "first,after" {
+		t.Errorf("got variables %v, want the unused firstChild removed", variables)
+	}
+}
+
+func TestSelectColumnsRequestsOnlySelectedFields(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{}, stringQual("state_name", "Done"))
+	q.QueryContext.Columns = []string{"id", "title", "sla_status"}
+
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	got := entityFields(t, m.Requests()[0].Query)
+	want := []string{"canceledAt", "completedAt", "id", "slaBreachesAt", "slaStartedAt", "title"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got fields %v, want %v", got, want)
+	}
+}
+
+func TestSelectAttachmentTitle(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{})
+	q.QueryContext.Columns = []string{"title"}
+
+	if _, err := listAttachments(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	got := entityFields(t, m.Requests()[0].Query)
+	if strings.Join(got, ",") != "id,title" {
+		t.Errorf("got fields %v, want id and title", got)
+	}
+	assertJSON(t, columnValues(t, tableLinearAttachment(context.Background()), q.rows, "title"), `[{"title": "Pull request"}]`)
+}
+
+// TestColumnsHaveFields checks that every column is either read from the
+// field named after it or mapped to its fields, so that selecting it keeps
+// the fields it needs.
+func TestColumnsHaveFields(t *testing.T) {
+	tests := []struct {
+		table  func(context.Context) *plugin.Table
+		list   plugin.HydrateFunc
+		get    plugin.HydrateFunc
+		fields columnFields
+	}{
+		{tableLinearAttachment, listAttachments, getAttachment, attachmentColumnFields},
+		{tableLinearComment, listComments, getComment, commentColumnFields},
+		{tableLinearIntegration, listIntegrations, getIntegration, integrationColumnFields},
+		{tableLinearIssue, listIssues, getIssue, issueColumnFields},
+		{tableLinearIssueLabel, listIssueLabels, getIssueLabel, issueLabelColumnFields},
+		{tableLinearOrganization, nil, getOrganization, organizationColumnFields},
+		{tableLinearProject, listProjects, getProject, projectColumnFields},
+		{tableLinearTeam, listTeams, getTeam, teamColumnFields},
+		{tableLinearTeamMembership, listTeamMemberships, getTeamMembership, teamMembershipColumnFields},
+		{tableLinearUser, listUsers, getUser, userColumnFields},
+	}
+
+	for _, test := range tests {
+		table := test.table(context.Background())
+		t.Run(table.Name, func(t *testing.T) {
+			m := newMockLinear(t)
+			if test.list != nil {
+				if _, err := test.list(testContext(), newTestQuery(m, linearConfig{}).QueryData, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := test.get(testContext(), newTestQuery(m, linearConfig{}, stringQual("id", "some-id")).QueryData, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, req := range m.Requests() {
+				// nested pages of a row and related entities are not pruned
+				switch req.OperationName {
+				case "getIssueIds", "listTeamsByIds", "listUsersByIds", "listProjectsByIds":
+					continue
+				}
+				available := map[string]bool{}
+				for _, field := range entityFields(t, req.Query) {
+					available[field] = true
+				}
+				for _, column := range table.Columns {
+					// the workspace is read from the matrix item
+					if column.Hydrate != nil || column.Name == matrixKeyWorkspace {
+						continue
+					}
+					fields, mapped := test.fields[column.Name]
+					if !mapped {
+						fields = []string{strcase.ToLowerCamel(column.Name)}
+					}
+					// only columns transformed from other fields, or from their
+					// quals, may need no field
+					if mapped && len(fields) == 0 && column.Transform == nil {
+						t.Errorf("column %s is mapped to no field, but is read from the field named after it", column.Name)
+					}
+					for _, field := range fields {
+						if !available[field] {
+							t.Errorf("%s: column %s is read from %s, which is not queried", req.OperationName, column.Name, field)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// entityFields returns the sorted fields of the entity queried by query.
+func entityFields(t *testing.T, query string) []string {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		t.Fatalf("invalid query %s: %v", query, err)
+	}
+	names := fieldNames(*entitySelection(&doc.Operations[0].SelectionSet))
+	sort.Strings(names)
+	return names
+}
+
+func fieldNames(set ast.SelectionSet) []string {
+	var names []string
+	for _, selection := range set {
+		if field, ok := selection.(*ast.Field); ok {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
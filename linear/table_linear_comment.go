@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/turbot/steampipe-plugin-linear/gql"
-	"github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -14,26 +13,17 @@ import (
 
 func tableLinearComment(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_comment",
-		Description: "Linear Comment",
+		Name:              "linear_comment",
+		Description:       "Linear Comment",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
 			Hydrate: listComments,
-			KeyColumns: []*plugin.KeyColumn{
-				{
-					Name:      "created_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "updated_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:    "body",
+			KeyColumns: append(filterKeyColumns[gql.CommentFilter](commentQualFilters),
+				&plugin.KeyColumn{
+					Name:    "parent_id",
 					Require: plugin.Optional,
 				},
-			},
+			),
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -92,16 +82,34 @@ func tableLinearComment(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Transform:   transform.FromField("User"),
 			},
+			{
+				Name:        "user_id",
+				Description: "The unique identifier of the user who wrote the comment.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("User.Id"),
+			},
 			{
 				Name:        "parent",
 				Type:        proto.ColumnType_JSON,
 				Description: "The parent comment under which the current comment is nested.",
 			},
+			{
+				Name:        "parent_id",
+				Description: "The unique identifier of the parent comment under which the current comment is nested.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parent.Id"),
+			},
 			{
 				Name:        "issue",
 				Type:        proto.ColumnType_JSON,
 				Description: "The issue that the comment is associated with.",
 			},
+			{
+				Name:        "issue_id",
+				Description: "The unique identifier of the issue that the comment is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Issue.Id"),
+			},
 
 			// Steampipe standard columns
 			{
@@ -114,6 +122,16 @@ func tableLinearComment(ctx context.Context) *plugin.Table {
 	}
 }
 
+// commentColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var commentColumnFields = columnFields{
+	"comment_user": {"user"},
+	"user_id":      {"user"},
+	"parent_id":    {"parent"},
+	"issue_id":     {"issue"},
+	"title":        nil,
+}
+
 // LIST FUNCTION
 
 func listComments(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -135,8 +153,13 @@ func listComments(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 	// set the requested filters
 	filters := setCommentFilters(d, ctx)
 
+	// walk the parent comment's replies directly, if the parent is known
+	if d.EqualsQualString("parent_id") != "" {
+		return listCommentsByParent(ctx, d, conn, pageSize, &filters)
+	}
+
 	for {
-		listCommentResponse, err := gql.ListComments(ctx, conn.client, pageSize, endCursor, true, &filters)
+		listCommentResponse, err := gql.ListComments(ctx, conn.selectColumns(d, commentColumnFields), pageSize, endCursor, true, &filters)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_comment.listComments", "api_error", err)
 			return nil, err
@@ -158,6 +181,36 @@ func listComments(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 	return nil, nil
 }
 
+func listCommentsByParent(ctx context.Context, d *plugin.QueryData, conn *linearClient, pageSize int, filters *gql.CommentFilter) (interface{}, error) {
+	parentId := d.EqualsQualString("parent_id")
+	var endCursor string
+
+	for {
+		listCommentChildrenResponse, err := gql.ListCommentChildren(ctx, conn.selectColumns(d, commentColumnFields), &parentId, pageSize, endCursor, true, filters)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_comment.listCommentsByParent", "api_error", err)
+			return nil, err
+		}
+		if listCommentChildrenResponse.Comment == nil {
+			return nil, nil
+		}
+		for _, node := range listCommentChildrenResponse.Comment.Children.Nodes {
+			d.StreamListItem(ctx, node)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*listCommentChildrenResponse.Comment.Children.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *listCommentChildrenResponse.Comment.Children.PageInfo.EndCursor
+	}
+
+	return nil, nil
+}
+
 // HYDRATE FUNCTION
 
 func getComment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -174,7 +227,7 @@ func getComment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	getCommentResponse, err := gql.GetComment(ctx, conn.client, &id)
+	getCommentResponse, err := gql.GetComment(ctx, conn.selectColumns(d, commentColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_comment.getComment", "api_error", err)
 		return nil, err
@@ -183,52 +236,19 @@ func getComment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 	return getCommentResponse.Comment, nil
 }
 
+// commentQualFilters are the key columns pushed down to the filter of list calls
+var commentQualFilters = qualFilters{
+	"created_at": {field: "CreatedAt"},
+	"updated_at": {field: "UpdatedAt"},
+	"body":       {field: "Body"},
+	"issue_id":   {field: "Issue.Id"},
+	"user_id":    {field: "User.Id"},
+}
+
 // Set the requested filter
 func setCommentFilters(d *plugin.QueryData, ctx context.Context) gql.CommentFilter {
 	var filter gql.CommentFilter
-	if d.Quals["created_at"] != nil {
-		createdAt := &gql.DateComparator{}
-		for _, q := range d.Quals["created_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				createdAt.Eq = timestamp
-			case ">":
-				createdAt.Gt = timestamp
-			case ">=":
-				createdAt.Gte = timestamp
-			case "<":
-				createdAt.Lt = timestamp
-			case "<=":
-				createdAt.Lte = timestamp
-			}
-		}
-		filter.CreatedAt = createdAt
-	}
-	if d.Quals["updated_at"] != nil {
-		updatedAt := &gql.DateComparator{}
-		for _, q := range d.Quals["updated_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				updatedAt.Eq = timestamp
-			case ">":
-				updatedAt.Gt = timestamp
-			case ">=":
-				updatedAt.Gte = timestamp
-			case "<":
-				updatedAt.Lt = timestamp
-			case "<=":
-				updatedAt.Lte = timestamp
-			}
-		}
-		filter.UpdatedAt = updatedAt
-	}
-	if d.EqualsQuals["body"] != nil {
-		body := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("body")),
-		}
-		filter.Body = body
-	}
+	setFilters(d, &filter, commentQualFilters)
+
 	return filter
 }
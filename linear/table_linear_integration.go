@@ -13,10 +13,17 @@ import (
 
 func tableLinearIntegration(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_integration",
-		Description: "Linear Integration",
+		Name:              "linear_integration",
+		Description:       "Linear Integration",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
 			Hydrate: listIntegrations,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "service",
+					Require: plugin.Optional,
+				},
+			},
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -53,6 +60,18 @@ func tableLinearIntegration(ctx context.Context) *plugin.Table {
 				Description: "The team that the integration is associated with.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "team_id",
+				Description: "The unique identifier of the team that the integration is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Team.Id"),
+			},
+			{
+				Name:        "integrations_settings",
+				Description: "Settings for all integrations associated with the integration's team.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Team.IntegrationsSettings"),
+			},
 			{
 				Name:        "creator",
 				Description: "The user that added the integration.",
@@ -75,6 +94,14 @@ func tableLinearIntegration(ctx context.Context) *plugin.Table {
 	}
 }
 
+// integrationColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var integrationColumnFields = columnFields{
+	"team_id":               {"team"},
+	"integrations_settings": {"team"},
+	"title":                 nil,
+}
+
 // LIST FUNCTION
 
 func listIntegrations(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -93,13 +120,19 @@ func listIntegrations(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrat
 		}
 	}
 
+	service := d.EqualsQualString("service")
+
 	for {
-		listIntegrationResponse, err := gql.ListIntegrations(ctx, conn.client, pageSize, endCursor, true)
+		listIntegrationResponse, err := gql.ListIntegrations(ctx, conn.selectColumns(d, integrationColumnFields), pageSize, endCursor, true)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_integration.listIntegrations", "api_error", err)
 			return nil, err
 		}
 		for _, node := range listIntegrationResponse.Integrations.Nodes {
+			// the integrations API does not accept a filter, so the service is matched here
+			if service != "" && (node.Service == nil || *node.Service != service) {
+				continue
+			}
 			d.StreamListItem(ctx, node)
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -132,7 +165,7 @@ func getIntegration(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateD
 		return nil, err
 	}
 
-	getIntegrationResponse, err := gql.GetIntegration(ctx, conn.client, &id)
+	getIntegrationResponse, err := gql.GetIntegration(ctx, conn.selectColumns(d, integrationColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_integration.getIntegration", "api_error", err)
 		return nil, err
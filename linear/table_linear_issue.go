@@ -2,82 +2,27 @@ package linear
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
+	"time"
 
-	"github.com/turbot/go-kit/types"
+	"github.com/Khan/genqlient/graphql"
 	"github.com/turbot/steampipe-plugin-linear/gql"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
 //// TABLE DEFINITION
 
 func tableLinearIssue(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_issue",
-		Description: "Linear Issue",
+		Name:              "linear_issue",
+		Description:       "Linear Issue",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
-			Hydrate: listIssues,
-			KeyColumns: []*plugin.KeyColumn{
-				{
-					Name:      "created_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "updated_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "number",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:    "title",
-					Require: plugin.Optional,
-				},
-				{
-					Name:      "priority",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "started_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "completed_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "canceled_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "auto_closed_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "auto_archived_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "due_date",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "snoozed_until_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-			},
+			Hydrate:    listIssues,
+			KeyColumns: filterKeyColumns[gql.IssueFilter](issueQualFilters),
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -204,31 +149,119 @@ func tableLinearIssue(ctx context.Context) *plugin.Table {
 				Description: "Returns the number of Attachment resources which are created by customer support ticketing systems (e.g. Zendesk).",
 				Type:        proto.ColumnType_INT,
 			},
+			{
+				Name:        "sla_started_at",
+				Description: "The time at which the issue's SLA began.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "sla_breaches_at",
+				Description: "The time at which the issue's SLA will breach.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "sla_status",
+				Description: "The status of the issue's SLA, derived by the plugin when the issue is fetched rather than returned by Linear. Possible values are: Breached, Completed, Failed, HighRisk (less than a quarter of the SLA window remains), MediumRisk (less than half remains) and LowRisk.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromValue().Transform(issueSlaStatus),
+			},
+			{
+				Name:        "started_triage_at",
+				Description: "The time at which the issue entered triage.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "triaged_at",
+				Description: "The time at which the issue left triage.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
 			{
 				Name:        "team",
 				Description: "The team that the issue is associated with.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "team_id",
+				Description: "The unique identifier of the team that the issue is associated with.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "team_key",
+				Description: "The key of the team that the issue is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Team.Key"),
+			},
+			{
+				Name:        "team_name",
+				Description: "The name of the team that the issue is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Team.Name"),
+			},
 			{
 				Name:        "cycle",
 				Description: "The cycle that the issue is associated with.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "cycle_id",
+				Description: "The unique identifier of the cycle that the issue is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Cycle.Id"),
+			},
 			{
 				Name:        "project",
 				Description: "The project that the issue is associated with.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "project_id",
+				Description: "The unique identifier of the project that the issue is associated with.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "project_name",
+				Description: "The name of the project that the issue is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Project.Name"),
+			},
 			{
 				Name:        "creator",
 				Description: "The user who created the issue.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "creator_id",
+				Description: "The unique identifier of the user who created the issue.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "creator_name",
+				Description: "The full name of the user who created the issue.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Creator.Name"),
+			},
 			{
 				Name:        "assignee",
 				Description: "The user to whom the issue is assigned to.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "assignee_id",
+				Description: "The unique identifier of the user to whom the issue is assigned to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "assignee_name",
+				Description: "The full name of the user to whom the issue is assigned to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Assignee.Name"),
+			},
+			{
+				Name:        "assignee_email",
+				Description: "The email address of the user to whom the issue is assigned to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Assignee.Email"),
+			},
 			{
 				Name:        "snoozed_by",
 				Description: "The user who snoozed the issue.",
@@ -244,6 +277,12 @@ func tableLinearIssue(ctx context.Context) *plugin.Table {
 				Description: "The parent of the issue.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "parent_id",
+				Description: "The unique identifier of the parent of the issue.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parent.Id"),
+			},
 			{
 				Name:        "project_milestone",
 				Description: "The projectMilestone that the issue is associated with.",
@@ -260,6 +299,112 @@ func tableLinearIssue(ctx context.Context) *plugin.Table {
 	}
 }
 
+// issueColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var issueColumnFields = columnFields{
+	"sla_status":     {"slaStartedAt", "slaBreachesAt", "completedAt", "canceledAt"},
+	"team_id":        {"team"},
+	"team_key":       {"team"},
+	"team_name":      {"team"},
+	"cycle_id":       {"cycle"},
+	"project_id":     {"project"},
+	"project_name":   {"project"},
+	"creator_id":     {"creator"},
+	"creator_name":   {"creator"},
+	"assignee_id":    {"assignee"},
+	"assignee_name":  {"assignee"},
+	"assignee_email": {"assignee"},
+	"parent_id":      {"parent"},
+}
+
+// issueRow is an issue with its team, project, creator and assignee read from
+// the entity loaders of the query, rather than embedded in every issue.
+type issueRow struct {
+	gql.IssueNode
+	Team       *gql.BatchTeam
+	Project    *gql.BatchProject
+	Creator    *gql.BatchUser
+	Assignee   *gql.BatchUser
+	TeamId     *string
+	ProjectId  *string
+	CreatorId  *string
+	AssigneeId *string
+}
+
+// issueRelations loads the related entities of the issues of a query, for the
+// relations with selected columns. The teams, users and projects are fetched
+// once per distinct id, in batches.
+type issueRelations struct {
+	teams    *entityLoader[*gql.BatchTeam]
+	users    *entityLoader[*gql.BatchUser]
+	projects *entityLoader[*gql.BatchProject]
+
+	team, project, creator, assignee bool
+}
+
+func newIssueRelations(d *plugin.QueryData, conn *linearClient) *issueRelations {
+	return &issueRelations{
+		teams:    newTeamLoader(conn),
+		users:    newUserLoader(conn),
+		projects: newProjectLoader(conn),
+		team:     columnSelected(d, "team", "team_key", "team_name"),
+		project:  columnSelected(d, "project", "project_name"),
+		creator:  columnSelected(d, "creator", "creator_name"),
+		assignee: columnSelected(d, "assignee", "assignee_name", "assignee_email"),
+	}
+}
+
+// rows returns the rows of the issues, with their related entities loaded.
+func (r *issueRelations) rows(ctx context.Context, nodes []*gql.IssueNode) ([]*issueRow, error) {
+	var teamIds, projectIds, userIds []string
+	for _, node := range nodes {
+		if r.team && node.Team != nil {
+			teamIds = append(teamIds, *node.Team.Id)
+		}
+		if r.project && node.Project != nil {
+			projectIds = append(projectIds, *node.Project.Id)
+		}
+		if r.creator && node.Creator != nil {
+			userIds = append(userIds, *node.Creator.Id)
+		}
+		if r.assignee && node.Assignee != nil {
+			userIds = append(userIds, *node.Assignee.Id)
+		}
+	}
+
+	teams, err := r.teams.loadMany(ctx, teamIds)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := r.projects.loadMany(ctx, projectIds)
+	if err != nil {
+		return nil, err
+	}
+	users, err := r.users.loadMany(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*issueRow, len(nodes))
+	for i, node := range nodes {
+		row := &issueRow{IssueNode: *node}
+		if node.Team != nil {
+			row.TeamId, row.Team = node.Team.Id, teams[*node.Team.Id]
+		}
+		if node.Project != nil {
+			row.ProjectId, row.Project = node.Project.Id, projects[*node.Project.Id]
+		}
+		if node.Creator != nil {
+			row.CreatorId, row.Creator = node.Creator.Id, users[*node.Creator.Id]
+		}
+		if node.Assignee != nil {
+			row.AssigneeId, row.Assignee = node.Assignee.Id, users[*node.Assignee.Id]
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
 // LIST FUNCTION
 
 func listIssues(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -269,8 +414,6 @@ func listIssues(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		return nil, err
 	}
 
-	var endCursor string
-
 	// set page size
 	var pageSize int = int(conn.pageSize)
 	if d.QueryContext.Limit != nil {
@@ -282,27 +425,251 @@ func listIssues(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	// set the requested filters
 	filters := setIssueFilters(d, ctx)
 
+	// related entities are shared by the shards of the query
+	relations := newIssueRelations(d, conn)
+
+	// full scans are read from the response cache when configured, and
+	// split by creation time when they are fetched in full
+	if conn.cache != nil && d.QueryContext.Limit == nil {
+		return nil, listIssuesCached(ctx, d, conn, relations, pageSize, &filters)
+	}
+	return nil, listIssuesFull(ctx, d, conn, relations, pageSize, &filters, nil)
+}
+
+// listIssuesFull streams all the issues matching the filter, writing them to
+// the scan when it is cached.
+func listIssuesFull(ctx context.Context, d *plugin.QueryData, conn *linearClient, relations *issueRelations, pageSize int, filters *gql.IssueFilter, scan *scanWriter[*gql.IssueNode]) error {
+	if conn.issueScanShards > 1 && d.QueryContext.Limit == nil {
+		return listIssuesSharded(ctx, d, conn, relations, pageSize, filters, scan)
+	}
+	return listIssuePages(ctx, d, conn, relations, pageSize, filters, scan)
+}
+
+// listIssuePages streams the issues matching the filter, page by page.
+func listIssuePages(ctx context.Context, d *plugin.QueryData, conn *linearClient, relations *issueRelations, pageSize int, filters *gql.IssueFilter, scan *scanWriter[*gql.IssueNode]) error {
+	var endCursor string
+
 	for {
-		listIssueResponse, err := gql.ListIssues(ctx, conn.client, pageSize, endCursor, true, &filters)
+		listIssueResponse, err := gql.ListIssues(ctx, conn.selectColumns(d, issueColumnFields), pageSize, endCursor, true, filters)
 		if err != nil {
-			plugin.Logger(ctx).Error("linear_issue.listIssues", "api_error", err)
-			return nil, err
+			plugin.Logger(ctx).Error("linear_issue.listIssuePages", "api_error", err)
+			return err
+		}
+		scan.write(listIssueResponse.Issues.Nodes...)
+		more, err := streamIssues(ctx, d, relations, listIssueResponse.Issues.Nodes)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_issue.listIssuePages", "api_error", err)
+			return err
 		}
-		for _, node := range listIssueResponse.Issues.Nodes {
-			d.StreamListItem(ctx, node)
+		if !more || !*listIssueResponse.Issues.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *listIssueResponse.Issues.PageInfo.EndCursor
+	}
 
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
+	return nil
+}
+
+// streamIssues streams the rows of the issues, and reports whether the query
+// wants more rows.
+func streamIssues(ctx context.Context, d *plugin.QueryData, relations *issueRelations, nodes []*gql.IssueNode) (bool, error) {
+	rows, err := relations.rows(ctx, nodes)
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		d.StreamListItem(ctx, row)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return false, nil
 		}
+	}
+	return true, nil
+}
+
+// listIssuesCached streams the issues matching the filter from the response
+// cache, after fetching the issues updated since the cache was last fetched.
+// Scans with no cached scan to update are fetched in full, and cached once
+// complete.
+func listIssuesCached(ctx context.Context, d *plugin.QueryData, conn *linearClient, relations *issueRelations, pageSize int, filters *gql.IssueFilter) error {
+	key, err := cacheKey("listIssues", selectedFields(d, issueColumnFields), filters)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.listIssuesCached", "cache_error", err)
+		return err
+	}
+
+	started := conn.cache.now()
+	cached := openScan[*gql.IssueNode](conn.cache, key)
+	if cached == nil {
+		scan := createScan[*gql.IssueNode](conn.cache, key, scanHeader{ScannedAt: started, FetchedAt: started})
+		err := listIssuesFull(ctx, d, conn, relations, pageSize, filters, scan)
+		return commitIssueScan(ctx, d, scan, err)
+	}
+	defer cached.close()
+
+	since := cached.FetchedAt.Add(-cacheClockSkew)
+	updatedSince := &gql.IssueFilter{UpdatedAt: &gql.DateComparator{Gt: &since}}
+	updated, err := fetchIssues(ctx, conn.selectColumns(d, issueColumnFields), pageSize, &gql.IssueFilter{And: []*gql.IssueFilter{filters, updatedSince}})
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.listIssuesCached", "api_error", err)
+		return err
+	}
+	// issues updated so they no longer match the filter are only found by
+	// asking for all the updated issues
+	changed, err := fetchIssues(ctx, &selectingClient{wrapped: conn.client, keep: map[string]bool{"id": true}}, issueIdsPageSize, updatedSince)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.listIssuesCached", "api_error", err)
+		return err
+	}
+	var changedIds []string
+	for _, node := range changed {
+		changedIds = append(changedIds, *node.Id)
+	}
+
+	// the merged issues are streamed a page at a time, as they are read
+	scan := createScan[*gql.IssueNode](conn.cache, key, scanHeader{ScannedAt: cached.ScannedAt, FetchedAt: started})
+	var page []*gql.IssueNode
+	var streamErr error
+	streamPage := func() bool {
+		scan.write(page...)
+		more, err := streamIssues(ctx, d, relations, page)
+		page, streamErr = nil, err
+		return more && err == nil
+	}
+	err = mergeUpdates(cached, updated, changedIds, func(node *gql.IssueNode) string { return *node.Id }, func(node *gql.IssueNode) bool {
+		page = append(page, node)
+		return len(page) < pageSize || streamPage()
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.listIssuesCached", "cache_error", err)
+	} else if len(page) > 0 {
+		streamPage()
+	}
+	if streamErr != nil {
+		plugin.Logger(ctx).Error("linear_issue.listIssuesCached", "api_error", streamErr)
+		err = streamErr
+	}
+	return commitIssueScan(ctx, d, scan, err)
+}
+
+// commitIssueScan caches the scan when it is complete, and discards it when
+// it failed or the query stopped early.
+func commitIssueScan(ctx context.Context, d *plugin.QueryData, scan *scanWriter[*gql.IssueNode], err error) error {
+	if err != nil || d.RowsRemaining(ctx) == 0 {
+		scan.abort()
+		return err
+	}
+	// a cache which cannot be written does not fail the query
+	if err := scan.commit(); err != nil {
+		plugin.Logger(ctx).Warn("linear_issue.listIssuesCached", "cache_error", err)
+	}
+	return nil
+}
+
+// fetchIssues returns all the issues matching the filter.
+func fetchIssues(ctx context.Context, client graphql.Client, pageSize int, filters *gql.IssueFilter) ([]*gql.IssueNode, error) {
+	var nodes []*gql.IssueNode
+	var endCursor string
+	for {
+		listIssueResponse, err := gql.ListIssues(ctx, client, pageSize, endCursor, true, filters)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, listIssueResponse.Issues.Nodes...)
 		if !*listIssueResponse.Issues.PageInfo.HasNextPage {
-			break
+			return nodes, nil
 		}
 		endCursor = *listIssueResponse.Issues.PageInfo.EndCursor
 	}
+}
+
+// maxIssueScanWorkers bounds the number of shards paged through at once
+const maxIssueScanWorkers = 4
+
+// listIssuesSharded splits the creation dates of the issues matching the
+// filter into shards, and pages through the shards concurrently.
+func listIssuesSharded(ctx context.Context, d *plugin.QueryData, conn *linearClient, relations *issueRelations, pageSize int, filters *gql.IssueFilter, scan *scanWriter[*gql.IssueNode]) error {
+	rangeResponse, err := gql.GetIssueCreatedAtRange(ctx, conn.client, true, filters)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.listIssuesSharded", "api_error", err)
+		return err
+	}
+
+	// the direction of the createdAt ordering is not documented, so take
+	// the bounds from both ends
+	var createdAts []*time.Time
+	for _, node := range rangeResponse.FirstCreated.Nodes {
+		createdAts = append(createdAts, node.CreatedAt)
+	}
+	for _, node := range rangeResponse.LastCreated.Nodes {
+		createdAts = append(createdAts, node.CreatedAt)
+	}
+	var oldest, newest *time.Time
+	for _, createdAt := range createdAts {
+		if oldest == nil || createdAt.Before(*oldest) {
+			oldest = createdAt
+		}
+		if newest == nil || createdAt.After(*newest) {
+			newest = createdAt
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	return nil, nil
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var shardErr error
+	workers := make(chan struct{}, maxIssueScanWorkers)
+	for _, shard := range issueCreatedAtShards(*oldest, *newest, conn.issueScanShards) {
+		shardFilters := &gql.IssueFilter{And: []*gql.IssueFilter{filters, {CreatedAt: shard}}}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			if ctx.Err() != nil || d.RowsRemaining(ctx) == 0 {
+				return
+			}
+			if err := listIssuePages(ctx, d, conn, relations, pageSize, shardFilters, scan); err != nil {
+				errOnce.Do(func() {
+					shardErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	return shardErr
+}
+
+// issueCreatedAtShards splits [oldest, newest] into contiguous creation date
+// ranges of equal length.
+func issueCreatedAtShards(oldest, newest time.Time, shards int) []*gql.DateComparator {
+	step := newest.Sub(oldest) / time.Duration(shards)
+	if step <= 0 {
+		return []*gql.DateComparator{{Gte: &oldest, Lte: &newest}}
+	}
+
+	var comparators []*gql.DateComparator
+	for i := 0; i < shards; i++ {
+		start := oldest.Add(time.Duration(i) * step)
+		comparator := &gql.DateComparator{Gte: &start}
+		if i == shards-1 {
+			comparator.Lte = &newest
+		} else {
+			end := start.Add(step)
+			comparator.Lt = &end
+		}
+		comparators = append(comparators, comparator)
+	}
+	return comparators
 }
 
 // HYDRATE FUNCTION
@@ -321,234 +688,119 @@ func getIssue(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (
 		return nil, err
 	}
 
-	getIssueResponse, err := gql.GetIssue(ctx, conn.client, &id)
+	getIssueResponse, err := gql.GetIssue(ctx, conn.selectColumns(d, issueColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_issue.listIssues", "api_error", err)
 		return nil, err
 	}
+	if getIssueResponse.Issue == nil {
+		return nil, nil
+	}
+
+	// getIssue selects the same fields as listIssues
+	var node gql.IssueNode
+	issue, err := json.Marshal(getIssueResponse.Issue)
+	if err == nil {
+		err = json.Unmarshal(issue, &node)
+	}
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.getIssue", "json_error", err)
+		return nil, err
+	}
+	rows, err := newIssueRelations(d, conn).rows(ctx, []*gql.IssueNode{&node})
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue.getIssue", "api_error", err)
+		return nil, err
+	}
+
+	return rows[0], nil
+}
 
-	return getIssueResponse.Issue, nil
+// issueQualFilters are the key columns pushed down to the filter of list calls
+var issueQualFilters = qualFilters{
+	"created_at":       {field: "CreatedAt"},
+	"updated_at":       {field: "UpdatedAt"},
+	"number":           {field: "Number"},
+	"title":            {field: "Title"},
+	"priority":         {field: "Priority"},
+	"started_at":       {field: "StartedAt"},
+	"completed_at":     {field: "CompletedAt"},
+	"canceled_at":      {field: "CanceledAt"},
+	"auto_closed_at":   {field: "AutoClosedAt"},
+	"auto_archived_at": {field: "AutoArchivedAt"},
+	"due_date":         {field: "DueDate"},
+	"snoozed_until_at": {field: "SnoozedUntilAt"},
+	"triaged_at":       {field: "TriagedAt"},
+	"description":      {field: "Description"},
+	"estimate":         {field: "Estimate"},
+	"team_id":          {field: "Team.Id"},
+	"cycle_id":         {field: "Cycle.Id"},
+	"project_id":       {field: "Project.Id"},
+	"creator_id":       {field: "Creator.Id"},
+	"assignee_id":      {field: "Assignee.Id"},
+	"parent_id":        {field: "Parent.Id"},
 }
 
 // Set the requested filter
 func setIssueFilters(d *plugin.QueryData, ctx context.Context) gql.IssueFilter {
 	var filter gql.IssueFilter
-	if d.Quals["created_at"] != nil {
-		createdAt := &gql.DateComparator{}
-		for _, q := range d.Quals["created_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				createdAt.Eq = timestamp
-			case ">":
-				createdAt.Gt = timestamp
-			case ">=":
-				createdAt.Gte = timestamp
-			case "<":
-				createdAt.Lt = timestamp
-			case "<=":
-				createdAt.Lte = timestamp
-			}
-		}
-		filter.CreatedAt = createdAt
-	}
-	if d.Quals["updated_at"] != nil {
-		updatedAt := &gql.DateComparator{}
-		for _, q := range d.Quals["updated_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				updatedAt.Eq = timestamp
-			case ">":
-				updatedAt.Gt = timestamp
-			case ">=":
-				updatedAt.Gte = timestamp
-			case "<":
-				updatedAt.Lt = timestamp
-			case "<=":
-				updatedAt.Lte = timestamp
-			}
-		}
-		filter.UpdatedAt = updatedAt
-	}
-	if d.Quals["number"] != nil {
-		numberCom := &gql.NumberComparator{}
-		for _, q := range d.Quals["number"].Quals {
-			number := types.Float64(q.Value.GetDoubleValue())
-			switch q.Operator {
-			case "=":
-				numberCom.Eq = number
-			case ">":
-				numberCom.Gt = number
-			case ">=":
-				numberCom.Gte = number
-			case "<":
-				numberCom.Lt = number
-			case "<=":
-				numberCom.Lte = number
-			}
-		}
+	setFilters(d, &filter, issueQualFilters)
+	return filter
+}
+
+// TRANSFORM FUNCTION
 
-		filter.Number = numberCom
+type issueSla interface {
+	GetSlaStartedAt() *time.Time
+	GetSlaBreachesAt() *time.Time
+	GetCompletedAt() *time.Time
+	GetCanceledAt() *time.Time
+}
+
+// issueSlaStatus derives the SLA status of an issue from its SLA window. The
+// API does not return the status it filters on, so sla_status quals are not
+// pushed down, to keep the filtered rows consistent with the column.
+// The API does not document the risk thresholds Linear uses, so an open issue
+// is HighRisk when less than a quarter of the window remains, MediumRisk when
+// less than half remains and LowRisk otherwise. The status is computed against
+// the current time when the row is built, so rows served from the query cache
+// keep the status of when they were fetched.
+func issueSlaStatus(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	issue, ok := d.HydrateItem.(issueSla)
+	if !ok || issue.GetSlaBreachesAt() == nil {
+		return nil, nil
 	}
-	if d.EqualsQuals["title"] != nil {
-		title := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("title")),
-		}
-		filter.Title = title
-	}
-	if d.Quals["priority"] != nil {
-		priorityCom := &gql.NullableNumberComparator{}
-		for _, q := range d.Quals["priority"].Quals {
-			priority := types.Float64(q.Value.GetDoubleValue())
-			switch q.Operator {
-			case "=":
-				priorityCom.Eq = priority
-			case ">":
-				priorityCom.Gt = priority
-			case ">=":
-				priorityCom.Gte = priority
-			case "<":
-				priorityCom.Lt = priority
-			case "<=":
-				priorityCom.Lte = priority
-			}
-		}
+	breachesAt := *issue.GetSlaBreachesAt()
 
-		filter.Priority = priorityCom
-	}
-	if d.Quals["started_at"] != nil {
-		startedAt := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["started_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				startedAt.Eq = timestamp
-			case ">":
-				startedAt.Gt = timestamp
-			case ">=":
-				startedAt.Gte = timestamp
-			case "<":
-				startedAt.Lt = timestamp
-			case "<=":
-				startedAt.Lte = timestamp
-			}
-		}
-		filter.StartedAt = startedAt
-	}
-	if d.Quals["completed_at"] != nil {
-		completedAt := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["completed_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				completedAt.Eq = timestamp
-			case ">":
-				completedAt.Gt = timestamp
-			case ">=":
-				completedAt.Gte = timestamp
-			case "<":
-				completedAt.Lt = timestamp
-			case "<=":
-				completedAt.Lte = timestamp
-			}
-		}
-		filter.CompletedAt = completedAt
-	}
-	if d.Quals["canceled_at"] != nil {
-		canceledAt := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["canceled_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				canceledAt.Eq = timestamp
-			case ">":
-				canceledAt.Gt = timestamp
-			case ">=":
-				canceledAt.Gte = timestamp
-			case "<":
-				canceledAt.Lt = timestamp
-			case "<=":
-				canceledAt.Lte = timestamp
-			}
-		}
-		filter.CanceledAt = canceledAt
-	}
-	if d.Quals["auto_closed_at"] != nil {
-		autoClosedAt := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["auto_closed_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				autoClosedAt.Eq = timestamp
-			case ">":
-				autoClosedAt.Gt = timestamp
-			case ">=":
-				autoClosedAt.Gte = timestamp
-			case "<":
-				autoClosedAt.Lt = timestamp
-			case "<=":
-				autoClosedAt.Lte = timestamp
-			}
-		}
-		filter.AutoClosedAt = autoClosedAt
-	}
-	if d.Quals["auto_archived_at"] != nil {
-		autoArchivedAt := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["auto_archived_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				autoArchivedAt.Eq = timestamp
-			case ">":
-				autoArchivedAt.Gt = timestamp
-			case ">=":
-				autoArchivedAt.Gte = timestamp
-			case "<":
-				autoArchivedAt.Lt = timestamp
-			case "<=":
-				autoArchivedAt.Lte = timestamp
-			}
-		}
-		filter.AutoArchivedAt = autoArchivedAt
-	}
-	if d.Quals["due_date"] != nil {
-		dueDate := &gql.NullableTimelessDateComparator{}
-		for _, q := range d.Quals["due_date"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				dueDate.Eq = timestamp
-			case ">":
-				dueDate.Gt = timestamp
-			case ">=":
-				dueDate.Gte = timestamp
-			case "<":
-				dueDate.Lt = timestamp
-			case "<=":
-				dueDate.Lte = timestamp
-			}
-		}
-		filter.DueDate = dueDate
-	}
-	if d.Quals["snoozed_until_at"] != nil {
-		snoozedUntilAt := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["snoozed_until_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				snoozedUntilAt.Eq = timestamp
-			case ">":
-				snoozedUntilAt.Gt = timestamp
-			case ">=":
-				snoozedUntilAt.Gte = timestamp
-			case "<":
-				snoozedUntilAt.Lt = timestamp
-			case "<=":
-				snoozedUntilAt.Lte = timestamp
-			}
+	// an issue's SLA stops once it is completed or canceled
+	var closedAt *time.Time
+	if issue.GetCompletedAt() != nil {
+		closedAt = issue.GetCompletedAt()
+	} else if issue.GetCanceledAt() != nil {
+		closedAt = issue.GetCanceledAt()
+	}
+	if closedAt != nil {
+		if closedAt.After(breachesAt) {
+			return string(gql.SlaStatusFailed), nil
 		}
-		filter.SnoozedUntilAt = snoozedUntilAt
+		return string(gql.SlaStatusCompleted), nil
 	}
-	return filter
+
+	now := time.Now()
+	if !now.Before(breachesAt) {
+		return string(gql.SlaStatusBreached), nil
+	}
+	if issue.GetSlaStartedAt() == nil {
+		return string(gql.SlaStatusLowrisk), nil
+	}
+
+	window := breachesAt.Sub(*issue.GetSlaStartedAt())
+	remaining := breachesAt.Sub(now)
+	switch {
+	case remaining < window/4:
+		return string(gql.SlaStatusHighrisk), nil
+	case remaining < window/2:
+		return string(gql.SlaStatusMediumrisk), nil
+	}
+	return string(gql.SlaStatusLowrisk), nil
 }
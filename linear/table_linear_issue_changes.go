@@ -0,0 +1,180 @@
+package linear
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-linear/gql"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableLinearIssueChanges(ctx context.Context) *plugin.Table {
+	issues := tableLinearIssue(ctx)
+	return &plugin.Table{
+		Name:              "linear_issue_changes",
+		Description:       "Linear issues created, updated or archived since the consumer acknowledged its previous complete scan of the table.",
+		GetMatrixItemFunc: workspaceMatrix,
+		// scans stage and acknowledge watermarks, so each query must reach the
+		// list function rather than the query cache
+		Cache: &plugin.TableCacheOptions{Enabled: false},
+		List: &plugin.ListConfig{
+			Hydrate: listIssueChanges,
+			KeyColumns: append(plugin.KeyColumnSlice{
+				{
+					Name:    "consumer",
+					Require: plugin.Required,
+				},
+				{
+					Name:    "acknowledge",
+					Require: plugin.Optional,
+				},
+			}, issues.List.KeyColumns...),
+		},
+		Columns: append([]*plugin.Column{
+			{
+				Name:        "consumer",
+				Description: "The name the changes are tracked for. Each consumer has its own high-water mark.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("consumer"),
+			},
+			{
+				Name:        "acknowledge",
+				Description: "If true, commits the high-water mark of the consumer's latest complete scan and returns no rows.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromQual("acknowledge"),
+			},
+			{
+				Name:        "change_type",
+				Description: "How the issue changed since the acknowledged scan. Possible values are: created, updated and archived.",
+				Type:        proto.ColumnType_STRING,
+			},
+		}, issues.Columns...),
+	}
+}
+
+// issueChangeFields are the GraphQL fields the change type of an issue is
+// derived from, queried whichever columns are selected
+var issueChangeFields = []string{"createdAt", "updatedAt", "archivedAt"}
+
+// issueChangeKeyColumns are the key columns of the table which do not filter
+// the issues
+var issueChangeKeyColumns = []string{"consumer", "acknowledge"}
+
+// issueChangeRow is an issue changed since the watermark of the consumer.
+type issueChangeRow struct {
+	issueRow
+	ChangeType string
+}
+
+// LIST FUNCTION
+
+func listIssueChanges(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue_changes.listIssueChanges", "connection_error", err)
+		return nil, err
+	}
+	if conn.watermarks == nil {
+		return nil, errors.New("linear_issue_changes requires sync_state_dir to be set in the connection config")
+	}
+
+	// the watermark of each consumer is only committed when it acknowledges the
+	// scan, so a failed export returns the same changes again
+	key := "linear_issue_changes/" + d.EqualsQualString("consumer")
+	if acknowledge := d.EqualsQuals["acknowledge"]; acknowledge != nil && acknowledge.GetBoolValue() {
+		if _, err := conn.watermarks.acknowledge(key); err != nil {
+			plugin.Logger(ctx).Error("linear_issue_changes.listIssueChanges", "watermark_error", err)
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	watermark, err := conn.watermarks.load(key)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_issue_changes.listIssueChanges", "watermark_error", err)
+		return nil, err
+	}
+
+	// set page size
+	var pageSize int = int(conn.pageSize)
+	if d.QueryContext.Limit != nil {
+		if int(*d.QueryContext.Limit) < pageSize {
+			pageSize = int(*d.QueryContext.Limit)
+		}
+	}
+
+	// set the requested filters, restricted to the issues changed since the
+	// watermark. The first scan returns every issue.
+	filters := setIssueFilters(d, ctx)
+	if watermark != nil {
+		since := watermark.Add(-watermarkOverlap)
+		requested := filters
+		filters = gql.IssueFilter{And: []*gql.IssueFilter{&requested, {UpdatedAt: &gql.DateComparator{Gt: &since}}}}
+	}
+
+	client := conn.client
+	if keep := selectedFields(d, issueColumnFields); keep != nil {
+		for _, field := range issueChangeFields {
+			keep[field] = true
+		}
+		client = &selectingClient{wrapped: conn.client, keep: keep}
+	}
+	relations := newIssueRelations(d, conn)
+
+	var endCursor string
+	var highWater *time.Time
+	for {
+		listIssueResponse, err := gql.ListIssues(ctx, client, pageSize, endCursor, true, &filters)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_issue_changes.listIssueChanges", "api_error", err)
+			return nil, err
+		}
+		rows, err := relations.rows(ctx, listIssueResponse.Issues.Nodes)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_issue_changes.listIssueChanges", "api_error", err)
+			return nil, err
+		}
+		for _, row := range rows {
+			if row.UpdatedAt != nil && (highWater == nil || row.UpdatedAt.After(*highWater)) {
+				highWater = row.UpdatedAt
+			}
+			d.StreamListItem(ctx, &issueChangeRow{issueRow: *row, ChangeType: issueChangeType(row, watermark)})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*listIssueResponse.Issues.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *listIssueResponse.Issues.PageInfo.EndCursor
+	}
+
+	// only a complete scan of every change is staged for acknowledgement, as
+	// the issues excluded by quals or a limit have not been seen
+	if highWater != nil && !hasQuals(ctx, d, issueChangeKeyColumns...) && d.QueryContext.Limit == nil {
+		if err := conn.watermarks.stage(key, *highWater); err != nil {
+			plugin.Logger(ctx).Error("linear_issue_changes.listIssueChanges", "watermark_error", err)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// issueChangeType returns how the issue changed since the watermark.
+func issueChangeType(issue *issueRow, watermark *time.Time) string {
+	switch {
+	case watermark == nil || (issue.CreatedAt != nil && issue.CreatedAt.After(*watermark)):
+		return "created"
+	case issue.ArchivedAt != nil && issue.ArchivedAt.After(*watermark):
+		return "archived"
+	}
+	return "updated"
+}
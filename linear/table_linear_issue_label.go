@@ -2,9 +2,9 @@ package linear
 
 import (
 	"context"
+	"sync"
 
 	"github.com/turbot/steampipe-plugin-linear/gql"
-	"github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -14,16 +14,12 @@ import (
 
 func tableLinearIssueLabel(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_issue_label",
-		Description: "Linear Issue Label",
+		Name:              "linear_issue_label",
+		Description:       "Linear Issue Label",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
-			Hydrate: listIssueLabels,
-			KeyColumns: []*plugin.KeyColumn{
-				{
-					Name:    "name",
-					Require: plugin.Optional,
-				},
-			},
+			Hydrate:    listIssueLabels,
+			KeyColumns: filterKeyColumns[gql.IssueLabelFilter](issueLabelQualFilters),
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -103,6 +99,25 @@ func tableLinearIssueLabel(ctx context.Context) *plugin.Table {
 	}
 }
 
+// issueLabelColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var issueLabelColumnFields = columnFields{
+	"issue_ids": {"issues"},
+	"title":     {"name"},
+}
+
+const (
+	// nestedIssuePageSize is the size of the first page of issue ids nested
+	// in each label, kept small as its complexity is multiplied by the labels
+	nestedIssuePageSize = 50
+	// issueIdsPageSize is the size of the following pages of issue ids, the
+	// maximum of the API, lowered by the page size transport when too complex
+	issueIdsPageSize = 250
+	// maxIssueIdsWorkers bounds the number of labels whose issue ids are
+	// paged through at once
+	maxIssueIdsWorkers = 4
+)
+
 // LIST FUNCTION
 
 func listIssueLabels(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -122,35 +137,24 @@ func listIssueLabels(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		}
 	}
 
-	// set default pageSize for nested field issue ids
-	var issuePageSize int = 50
-
 	// set the requested filters
 	filters := setIssueLabelFilters(d, ctx)
 
 	for {
-		listIssueLabelResponse, err := gql.ListIssueLabels(ctx, conn.client, pageSize, issuePageSize, endCursor, true, &filters)
+		listIssueLabelResponse, err := gql.ListIssueLabels(ctx, conn.selectColumns(d, issueLabelColumnFields), pageSize, nestedIssuePageSize, endCursor, true, &filters)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_issue_label.listIssueLabels", "api_error", err)
 			return nil, err
 		}
-		for _, node := range listIssueLabelResponse.IssueLabels.Nodes {
-			if *node.Issues.PageInfo.HasNextPage {
-				endIssueCursor := *node.Issues.PageInfo.EndCursor
-				for {
-					getIssueIdsResponse, err := gql.GetIssueIds(ctx, conn.client, node.Id, issuePageSize, endIssueCursor, true)
-					if err != nil {
-						plugin.Logger(ctx).Error("linear_issue_label.listIssueLabels.GetIssueIds", "api_error", err)
-						return nil, err
-					}
-					issueNodes := fetchIssueNodesFromList(getIssueIdsResponse.IssueLabel.Issues.Nodes)
-					node.Issues.Nodes = append(node.Issues.Nodes, issueNodes...)
-					if !*getIssueIdsResponse.IssueLabel.Issues.PageInfo.HasNextPage {
-						break
-					}
-					endIssueCursor = *getIssueIdsResponse.IssueLabel.Issues.PageInfo.EndCursor
-				}
+		// the issues are only queried when issue_ids is selected
+		if columnSelected(d, "issue_ids") {
+			if err := listRemainingIssueIds(ctx, conn, listIssueLabelResponse.IssueLabels.Nodes); err != nil {
+				plugin.Logger(ctx).Error("linear_issue_label.listIssueLabels", "api_error", err)
+				return nil, err
 			}
+		}
+
+		for _, node := range listIssueLabelResponse.IssueLabels.Nodes {
 			d.StreamListItem(ctx, node)
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -167,6 +171,61 @@ func listIssueLabels(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 	return nil, nil
 }
 
+// listRemainingIssueIds pages through the issue ids of the labels with more
+// than the first page of issues, for a bounded number of labels at once.
+func listRemainingIssueIds(ctx context.Context, conn *linearClient, nodes []*gql.IssueLabelNode) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var labelErr error
+	workers := make(chan struct{}, maxIssueIdsWorkers)
+	for _, node := range nodes {
+		if node.Issues == nil || !*node.Issues.PageInfo.HasNextPage {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			if ctx.Err() != nil {
+				return
+			}
+			issueNodes, err := listIssueIds(ctx, conn, node.Id, *node.Issues.PageInfo.EndCursor)
+			if err != nil {
+				errOnce.Do(func() {
+					labelErr = err
+					cancel()
+				})
+				return
+			}
+			node.Issues.Nodes = append(node.Issues.Nodes, fetchIssueNodesFromList(issueNodes)...)
+		}()
+	}
+	wg.Wait()
+
+	return labelErr
+}
+
+// listIssueIds returns the issue ids of the label after the cursor.
+func listIssueIds(ctx context.Context, conn *linearClient, id *string, endCursor string) ([]*gql.GetIssuesNode, error) {
+	var issueNodes []*gql.GetIssuesNode
+	for {
+		getIssueIdsResponse, err := gql.GetIssueIds(ctx, conn.client, id, issueIdsPageSize, endCursor, true)
+		if err != nil {
+			return nil, err
+		}
+		issueNodes = append(issueNodes, getIssueIdsResponse.IssueLabel.Issues.Nodes...)
+		if !*getIssueIdsResponse.IssueLabel.Issues.PageInfo.HasNextPage {
+			return issueNodes, nil
+		}
+		endCursor = *getIssueIdsResponse.IssueLabel.Issues.PageInfo.EndCursor
+	}
+}
+
 func fetchIssueNodesFromList(nodes []*gql.GetIssuesNode) []*gql.ListIssuesNodes {
 	var issueNodes []*gql.ListIssuesNodes
 	for _, issueNode := range nodes {
@@ -188,37 +247,24 @@ func getIssueLabel(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		return nil, nil
 	}
 
-	var endCursor string
-
-	// set default pageSize for nested field issue ids
-	var issuePageSize int = 50
-
 	conn, err := connect(ctx, d)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_issue_label.getIssueLabel", "connection_error", err)
 		return nil, err
 	}
 
-	getIssueLabelResponse, err := gql.GetIssueLabel(ctx, conn.client, &id, issuePageSize, true)
+	getIssueLabelResponse, err := gql.GetIssueLabel(ctx, conn.selectColumns(d, issueLabelColumnFields), &id, issueIdsPageSize, true)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_issue_label.getIssueLabel", "api_error", err)
 		return nil, err
 	}
-	if *getIssueLabelResponse.IssueLabel.Issues.PageInfo.HasNextPage {
-		endCursor = *getIssueLabelResponse.IssueLabel.Issues.PageInfo.EndCursor
-		for {
-			getIssueIdsResponse, err := gql.GetIssueIds(ctx, conn.client, &id, issuePageSize, endCursor, true)
-			if err != nil {
-				plugin.Logger(ctx).Error("linear_issue_label.getIssueLabel.GetIssueIds", "api_error", err)
-				return nil, err
-			}
-			issueNodes := fetchIssueNodesFromGet(getIssueIdsResponse.IssueLabel.Issues.Nodes)
-			getIssueLabelResponse.IssueLabel.Issues.Nodes = append(getIssueLabelResponse.IssueLabel.Issues.Nodes, issueNodes...)
-			if !*getIssueIdsResponse.IssueLabel.Issues.PageInfo.HasNextPage {
-				break
-			}
-			endCursor = *getIssueIdsResponse.IssueLabel.Issues.PageInfo.EndCursor
+	if issues := getIssueLabelResponse.IssueLabel.Issues; issues != nil && *issues.PageInfo.HasNextPage && columnSelected(d, "issue_ids") {
+		issueNodes, err := listIssueIds(ctx, conn, &id, *issues.PageInfo.EndCursor)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_issue_label.getIssueLabel.GetIssueIds", "api_error", err)
+			return nil, err
 		}
+		issues.Nodes = append(issues.Nodes, fetchIssueNodesFromGet(issueNodes)...)
 	}
 
 	return getIssueLabelResponse.IssueLabel, nil
@@ -235,15 +281,15 @@ func fetchIssueNodesFromGet(nodes []*gql.GetIssuesNode) []*gql.GetIssueNode {
 	return issueNodes
 }
 
+// issueLabelQualFilters are the key columns pushed down to the filter of list calls
+var issueLabelQualFilters = qualFilters{
+	"name": {field: "Name"},
+}
+
 // Set the requested filter
 func setIssueLabelFilters(d *plugin.QueryData, ctx context.Context) gql.IssueLabelFilter {
 	var filter gql.IssueLabelFilter
-	if d.EqualsQuals["name"] != nil {
-		name := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("name")),
-		}
-		filter.Name = name
-	}
+	setFilters(d, &filter, issueLabelQualFilters)
 
 	return filter
 }
@@ -13,8 +13,9 @@ import (
 
 func tableLinearOrganization(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_organization",
-		Description: "Linear Organization",
+		Name:              "linear_organization",
+		Description:       "Linear Organization",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
 			Hydrate: getOrganization,
 		},
@@ -156,6 +157,12 @@ func tableLinearOrganization(ctx context.Context) *plugin.Table {
 	}
 }
 
+// organizationColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var organizationColumnFields = columnFields{
+	"title": {"name"},
+}
+
 // HYDRATE FUNCTION
 
 func getOrganization(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -165,7 +172,7 @@ func getOrganization(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, err
 	}
 
-	getOrganizationResponse, err := gql.GetOrganization(ctx, conn.client)
+	getOrganizationResponse, err := gql.GetOrganization(ctx, conn.selectColumns(d, organizationColumnFields))
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_organization.getOrganization", "api_error", err)
 		return nil, err
@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/turbot/steampipe-plugin-linear/gql"
-	"github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -14,44 +13,17 @@ import (
 
 func tableLinearProject(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_project",
-		Description: "Linear Project",
+		Name:              "linear_project",
+		Description:       "Linear Project",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
 			Hydrate: listProjects,
-			KeyColumns: []*plugin.KeyColumn{
-				{
-					Name:      "created_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "updated_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:    "name",
-					Require: plugin.Optional,
-				},
-				{
-					Name:    "state",
+			KeyColumns: append(filterKeyColumns[gql.ProjectFilter](projectQualFilters),
+				&plugin.KeyColumn{
+					Name:    "team_id",
 					Require: plugin.Optional,
 				},
-				{
-					Name:    "slug_id",
-					Require: plugin.Optional,
-				},
-				{
-					Name:      "start_date",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "target_date",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-			},
+			),
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -218,11 +190,55 @@ func tableLinearProject(ctx context.Context) *plugin.Table {
 				Description: "The user who created the project.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "creator_id",
+				Description: "The unique identifier of the user who created the project.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Creator.Id"),
+			},
 			{
 				Name:        "lead",
 				Description: "The project lead.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "lead_id",
+				Description: "The unique identifier of the project lead.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Lead.Id"),
+			},
+			{
+				Name:        "member_id",
+				Description: "The unique identifier of a user that is a member of the project.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("member_id"),
+			},
+			{
+				Name:        "roadmap_id",
+				Description: "The unique identifier of a roadmap that the project is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("roadmap_id"),
+			},
+			{
+				Name:        "team_id",
+				Description: "The unique identifier of a team that the project is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("team_id"),
+			},
+			{
+				Name:        "teams",
+				Description: "The teams that the project is associated with.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getProjectTeams,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "members",
+				Description: "The users that are members of the project.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getProjectMembers,
+				Transform:   transform.FromValue(),
+			},
 
 			// Steampipe standard columns
 			{
@@ -235,6 +251,17 @@ func tableLinearProject(ctx context.Context) *plugin.Table {
 	}
 }
 
+// projectColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var projectColumnFields = columnFields{
+	"creator_id": {"creator"},
+	"lead_id":    {"lead"},
+	"member_id":  nil,
+	"roadmap_id": nil,
+	"team_id":    nil,
+	"title":      {"name"},
+}
+
 // LIST FUNCTION
 
 func listProjects(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -256,8 +283,13 @@ func listProjects(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 	// set the requested filters
 	filters := setProjectFilters(d, ctx)
 
+	// walk the team's projects directly, if the team is known
+	if d.EqualsQualString("team_id") != "" {
+		return listProjectsByTeam(ctx, d, conn, pageSize, &filters)
+	}
+
 	for {
-		listProjectResponse, err := gql.ListProjects(ctx, conn.client, pageSize, endCursor, true, &filters)
+		listProjectResponse, err := gql.ListProjects(ctx, conn.selectColumns(d, projectColumnFields), pageSize, endCursor, true, &filters)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_project.listProjects", "api_error", err)
 			return nil, err
@@ -279,6 +311,36 @@ func listProjects(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 	return nil, nil
 }
 
+func listProjectsByTeam(ctx context.Context, d *plugin.QueryData, conn *linearClient, pageSize int, filters *gql.ProjectFilter) (interface{}, error) {
+	teamId := d.EqualsQualString("team_id")
+	var endCursor string
+
+	for {
+		listTeamProjectResponse, err := gql.ListTeamProjects(ctx, conn.selectColumns(d, projectColumnFields), &teamId, pageSize, endCursor, true, filters)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_project.listProjectsByTeam", "api_error", err)
+			return nil, err
+		}
+		if listTeamProjectResponse.Team == nil {
+			return nil, nil
+		}
+		for _, node := range listTeamProjectResponse.Team.Projects.Nodes {
+			d.StreamListItem(ctx, node)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*listTeamProjectResponse.Team.Projects.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *listTeamProjectResponse.Team.Projects.PageInfo.EndCursor
+	}
+
+	return nil, nil
+}
+
 // HYDRATE FUNCTION
 
 func getProject(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -295,7 +357,7 @@ func getProject(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	getProjectResponse, err := gql.GetProject(ctx, conn.client, &id)
+	getProjectResponse, err := gql.GetProject(ctx, conn.selectColumns(d, projectColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_project.getProject", "api_error", err)
 		return nil, err
@@ -304,103 +366,91 @@ func getProject(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 	return getProjectResponse.Project, nil
 }
 
-// Set the requested filter
-func setProjectFilters(d *plugin.QueryData, ctx context.Context) gql.ProjectFilter {
-	var filter gql.ProjectFilter
-	if d.Quals["created_at"] != nil {
-		createdAt := &gql.DateComparator{}
-		for _, q := range d.Quals["created_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				createdAt.Eq = timestamp
-			case ">":
-				createdAt.Gt = timestamp
-			case ">=":
-				createdAt.Gte = timestamp
-			case "<":
-				createdAt.Lt = timestamp
-			case "<=":
-				createdAt.Lte = timestamp
-			}
-		}
-		filter.CreatedAt = createdAt
+func getProjectTeams(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	project, ok := h.Item.(interface{ GetId() *string })
+	if !ok || project.GetId() == nil {
+		return nil, nil
 	}
-	if d.Quals["updated_at"] != nil {
-		updatedAt := &gql.DateComparator{}
-		for _, q := range d.Quals["updated_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				updatedAt.Eq = timestamp
-			case ">":
-				updatedAt.Gt = timestamp
-			case ">=":
-				updatedAt.Gte = timestamp
-			case "<":
-				updatedAt.Lt = timestamp
-			case "<=":
-				updatedAt.Lte = timestamp
-			}
-		}
-		filter.UpdatedAt = updatedAt
+
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_project.getProjectTeams", "connection_error", err)
+		return nil, err
 	}
-	if d.EqualsQuals["name"] != nil {
-		name := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("name")),
+
+	var endCursor string
+	teams := []*gql.ProjectTeamNode{}
+	for {
+		listProjectTeamResponse, err := gql.ListProjectTeams(ctx, conn.client, project.GetId(), int(conn.pageSize), endCursor, true)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_project.getProjectTeams", "api_error", err)
+			return nil, err
 		}
-		filter.Name = name
-	}
-	if d.EqualsQuals["state"] != nil {
-		state := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("state")),
+		if listProjectTeamResponse.Project == nil {
+			return nil, nil
 		}
-		filter.State = state
-	}
-	if d.EqualsQuals["slug_id"] != nil {
-		slug_id := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("slug_id")),
+		teams = append(teams, listProjectTeamResponse.Project.Teams.Nodes...)
+		if !*listProjectTeamResponse.Project.Teams.PageInfo.HasNextPage {
+			break
 		}
-		filter.SlugId = slug_id
+		endCursor = *listProjectTeamResponse.Project.Teams.PageInfo.EndCursor
 	}
-	if d.Quals["start_date"] != nil {
-		startDate := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["start_date"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				startDate.Eq = timestamp
-			case ">":
-				startDate.Gt = timestamp
-			case ">=":
-				startDate.Gte = timestamp
-			case "<":
-				startDate.Lt = timestamp
-			case "<=":
-				startDate.Lte = timestamp
-			}
-		}
-		filter.StartDate = startDate
+
+	return teams, nil
+}
+
+func getProjectMembers(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	project, ok := h.Item.(interface{ GetId() *string })
+	if !ok || project.GetId() == nil {
+		return nil, nil
 	}
-	if d.Quals["target_date"] != nil {
-		targetDate := &gql.NullableDateComparator{}
-		for _, q := range d.Quals["target_date"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				targetDate.Eq = timestamp
-			case ">":
-				targetDate.Gt = timestamp
-			case ">=":
-				targetDate.Gte = timestamp
-			case "<":
-				targetDate.Lt = timestamp
-			case "<=":
-				targetDate.Lte = timestamp
-			}
+
+	conn, err := connect(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("linear_project.getProjectMembers", "connection_error", err)
+		return nil, err
+	}
+
+	var endCursor string
+	members := []*gql.ProjectMemberNode{}
+	for {
+		listProjectMemberResponse, err := gql.ListProjectMembers(ctx, conn.client, project.GetId(), int(conn.pageSize), endCursor, true)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_project.getProjectMembers", "api_error", err)
+			return nil, err
+		}
+		if listProjectMemberResponse.Project == nil {
+			return nil, nil
+		}
+		members = append(members, listProjectMemberResponse.Project.Members.Nodes...)
+		if !*listProjectMemberResponse.Project.Members.PageInfo.HasNextPage {
+			break
 		}
-		filter.TargetDate = targetDate
+		endCursor = *listProjectMemberResponse.Project.Members.PageInfo.EndCursor
 	}
 
+	return members, nil
+}
+
+// projectQualFilters are the key columns pushed down to the filter of list calls
+var projectQualFilters = qualFilters{
+	"created_at":  {field: "CreatedAt"},
+	"updated_at":  {field: "UpdatedAt"},
+	"name":        {field: "Name"},
+	"state":       {field: "State"},
+	"slug_id":     {field: "SlugId"},
+	"start_date":  {field: "StartDate"},
+	"target_date": {field: "TargetDate"},
+	"lead_id":     {field: "Lead.Id"},
+	"creator_id":  {field: "Creator.Id"},
+	"member_id":   {field: "Members.Id", operators: []string{"="}},
+	"roadmap_id":  {field: "Roadmaps.Some.Id", operators: []string{"="}},
+}
+
+// Set the requested filter
+func setProjectFilters(d *plugin.QueryData, ctx context.Context) gql.ProjectFilter {
+	var filter gql.ProjectFilter
+	setFilters(d, &filter, projectQualFilters)
+
 	return filter
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/turbot/steampipe-plugin-linear/gql"
-	"github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -14,30 +13,12 @@ import (
 
 func tableLinearTeam(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_team",
-		Description: "Linear Team",
+		Name:              "linear_team",
+		Description:       "Linear Team",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
-			Hydrate: listTeams,
-			KeyColumns: []*plugin.KeyColumn{
-				{
-					Name:    "key",
-					Require: plugin.Optional,
-				},
-				{
-					Name:    "name",
-					Require: plugin.Optional,
-				},
-				{
-					Name:      "created_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "updated_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-			},
+			Hydrate:    listTeams,
+			KeyColumns: filterKeyColumns[gql.TeamFilter](teamQualFilters),
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -301,6 +282,12 @@ func tableLinearTeam(ctx context.Context) *plugin.Table {
 	}
 }
 
+// teamColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var teamColumnFields = columnFields{
+	"title": {"name"},
+}
+
 // LIST FUNCTION
 
 func listTeams(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -324,7 +311,7 @@ func listTeams(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	filters := setTeamFilters(d, ctx)
 
 	for {
-		listTeamResponse, err := gql.ListTeams(ctx, conn.client, pageSize, endCursor, true, &filters)
+		listTeamResponse, err := gql.ListTeams(ctx, conn.selectColumns(d, teamColumnFields), pageSize, endCursor, true, &filters)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_team.listTeams", "api_error", err)
 			return nil, err
@@ -363,7 +350,7 @@ func getTeam(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 		return nil, err
 	}
 
-	getTeamResponse, err := gql.GetTeam(ctx, conn.client, &id)
+	getTeamResponse, err := gql.GetTeam(ctx, conn.selectColumns(d, teamColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_team.getTeam", "api_error", err)
 		return nil, err
@@ -372,59 +359,19 @@ func getTeam(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 	return getTeamResponse.Team, nil
 }
 
+// teamQualFilters are the key columns pushed down to the filter of list calls
+var teamQualFilters = qualFilters{
+	"created_at":  {field: "CreatedAt"},
+	"updated_at":  {field: "UpdatedAt"},
+	"name":        {field: "Name"},
+	"key":         {field: "Key"},
+	"description": {field: "Description"},
+}
+
 // Set the requested filter
 func setTeamFilters(d *plugin.QueryData, ctx context.Context) gql.TeamFilter {
 	var filter gql.TeamFilter
-	if d.Quals["created_at"] != nil {
-		createdAt := &gql.DateComparator{}
-		for _, q := range d.Quals["created_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				createdAt.Eq = timestamp
-			case ">":
-				createdAt.Gt = timestamp
-			case ">=":
-				createdAt.Gte = timestamp
-			case "<":
-				createdAt.Lt = timestamp
-			case "<=":
-				createdAt.Lte = timestamp
-			}
-		}
-		filter.CreatedAt = createdAt
-	}
-	if d.Quals["updated_at"] != nil {
-		updatedAt := &gql.DateComparator{}
-		for _, q := range d.Quals["updated_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				updatedAt.Eq = timestamp
-			case ">":
-				updatedAt.Gt = timestamp
-			case ">=":
-				updatedAt.Gte = timestamp
-			case "<":
-				updatedAt.Lt = timestamp
-			case "<=":
-				updatedAt.Lte = timestamp
-			}
-		}
-		filter.UpdatedAt = updatedAt
-	}
-	if d.EqualsQuals["name"] != nil {
-		name := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("name")),
-		}
-		filter.Name = name
-	}
-	if d.EqualsQuals["key"] != nil {
-		key := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("key")),
-		}
-		filter.Key = key
-	}
+	setFilters(d, &filter, teamQualFilters)
 
 	return filter
 }
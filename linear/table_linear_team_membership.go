@@ -13,10 +13,21 @@ import (
 
 func tableLinearTeamMembership(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_team_membership",
-		Description: "Linear Team Membership",
+		Name:              "linear_team_membership",
+		Description:       "Linear Team Membership",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
 			Hydrate: listTeamMemberships,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "team_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "user_id",
+					Require: plugin.Optional,
+				},
+			},
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -58,6 +69,18 @@ func tableLinearTeamMembership(ctx context.Context) *plugin.Table {
 				Description: "The team that the membership is associated with.",
 				Type:        proto.ColumnType_JSON,
 			},
+			{
+				Name:        "team_id",
+				Description: "The unique identifier of the team that the membership is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Team.Id"),
+			},
+			{
+				Name:        "team_key",
+				Description: "The key of the team that the membership is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Team.Key"),
+			},
 			// user is a keyword, so here transform function has been used
 			{
 				Name:        "membership_user",
@@ -65,6 +88,18 @@ func tableLinearTeamMembership(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Transform:   transform.FromField("User"),
 			},
+			{
+				Name:        "user_id",
+				Description: "The unique identifier of the user that the membership is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("User.Id"),
+			},
+			{
+				Name:        "user_email",
+				Description: "The email of the user that the membership is associated with.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("User.Email"),
+			},
 
 			// Steampipe standard columns
 			{
@@ -77,6 +112,17 @@ func tableLinearTeamMembership(ctx context.Context) *plugin.Table {
 	}
 }
 
+// teamMembershipColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var teamMembershipColumnFields = columnFields{
+	"team_id":         {"team"},
+	"team_key":        {"team"},
+	"membership_user": {"user"},
+	"user_id":         {"user"},
+	"user_email":      {"user"},
+	"title":           nil,
+}
+
 // LIST FUNCTION
 
 func listTeamMemberships(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -96,8 +142,16 @@ func listTeamMemberships(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 		}
 	}
 
+	// walk the team's or user's memberships directly, if either is known
+	if d.EqualsQualString("team_id") != "" {
+		return listTeamMembershipsByTeam(ctx, d, conn, pageSize)
+	}
+	if d.EqualsQualString("user_id") != "" {
+		return listTeamMembershipsByUser(ctx, d, conn, pageSize)
+	}
+
 	for {
-		listTeamMembershipResponse, err := gql.ListTeamMemberships(ctx, conn.client, pageSize, endCursor, true)
+		listTeamMembershipResponse, err := gql.ListTeamMemberships(ctx, conn.selectColumns(d, teamMembershipColumnFields), pageSize, endCursor, true)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_team_membership.listTeamMemberships", "api_error", err)
 			return nil, err
@@ -120,6 +174,66 @@ func listTeamMemberships(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 	return nil, nil
 }
 
+func listTeamMembershipsByTeam(ctx context.Context, d *plugin.QueryData, conn *linearClient, pageSize int) (interface{}, error) {
+	teamId := d.EqualsQualString("team_id")
+	var endCursor string
+
+	for {
+		listTeamMembershipResponse, err := gql.ListTeamMembershipsByTeam(ctx, conn.selectColumns(d, teamMembershipColumnFields), &teamId, pageSize, endCursor, true)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_team_membership.listTeamMembershipsByTeam", "api_error", err)
+			return nil, err
+		}
+		if listTeamMembershipResponse.Team == nil {
+			return nil, nil
+		}
+		for _, node := range listTeamMembershipResponse.Team.Memberships.Nodes {
+			d.StreamListItem(ctx, node)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*listTeamMembershipResponse.Team.Memberships.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *listTeamMembershipResponse.Team.Memberships.PageInfo.EndCursor
+	}
+
+	return nil, nil
+}
+
+func listTeamMembershipsByUser(ctx context.Context, d *plugin.QueryData, conn *linearClient, pageSize int) (interface{}, error) {
+	userId := d.EqualsQualString("user_id")
+	var endCursor string
+
+	for {
+		listTeamMembershipResponse, err := gql.ListTeamMembershipsByUser(ctx, conn.selectColumns(d, teamMembershipColumnFields), &userId, pageSize, endCursor, true)
+		if err != nil {
+			plugin.Logger(ctx).Error("linear_team_membership.listTeamMembershipsByUser", "api_error", err)
+			return nil, err
+		}
+		if listTeamMembershipResponse.User == nil {
+			return nil, nil
+		}
+		for _, node := range listTeamMembershipResponse.User.TeamMemberships.Nodes {
+			d.StreamListItem(ctx, node)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+		if !*listTeamMembershipResponse.User.TeamMemberships.PageInfo.HasNextPage {
+			break
+		}
+		endCursor = *listTeamMembershipResponse.User.TeamMemberships.PageInfo.EndCursor
+	}
+
+	return nil, nil
+}
+
 // HYDRATE FUNCTION
 
 func getTeamMembership(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
@@ -136,7 +250,7 @@ func getTeamMembership(ctx context.Context, d *plugin.QueryData, h *plugin.Hydra
 		return nil, err
 	}
 
-	getTeamMembershipResponse, err := gql.GetTeamMembership(ctx, conn.client, &id)
+	getTeamMembershipResponse, err := gql.GetTeamMembership(ctx, conn.selectColumns(d, teamMembershipColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_team_membership.getTeamMembership", "api_error", err)
 		return nil, err
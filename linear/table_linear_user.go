@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/turbot/steampipe-plugin-linear/gql"
-	"github.com/turbot/go-kit/types"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -14,46 +13,12 @@ import (
 
 func tableLinearUser(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "linear_user",
-		Description: "Linear User",
+		Name:              "linear_user",
+		Description:       "Linear User",
+		GetMatrixItemFunc: workspaceMatrix,
 		List: &plugin.ListConfig{
-			Hydrate: listUsers,
-			KeyColumns: []*plugin.KeyColumn{
-				{
-					Name:    "active",
-					Require: plugin.Optional,
-				},
-				{
-					Name:    "admin",
-					Require: plugin.Optional,
-				},
-				{
-					Name:    "display_name",
-					Require: plugin.Optional,
-				},
-				{
-					Name:      "created_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:      "updated_at",
-					Require:   plugin.Optional,
-					Operators: []string{"=", ">", ">=", "<=", "<"},
-				},
-				{
-					Name:    "email",
-					Require: plugin.Optional,
-				},
-				{
-					Name:    "is_me",
-					Require: plugin.Optional,
-				},
-				{
-					Name:    "name",
-					Require: plugin.Optional,
-				},
-			},
+			Hydrate:    listUsers,
+			KeyColumns: filterKeyColumns[gql.UserFilter](userQualFilters),
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: plugin.SingleColumn("id"),
@@ -134,7 +99,6 @@ func tableLinearUser(ctx context.Context) *plugin.Table {
 				Name:        "last_seen",
 				Description: "The last time the user was seen online. If null, the user is currently online.",
 				Type:        proto.ColumnType_TIMESTAMP,
-
 			},
 			{
 				Name:        "name",
@@ -188,6 +152,12 @@ func tableLinearUser(ctx context.Context) *plugin.Table {
 	}
 }
 
+// userColumnFields are the GraphQL fields of the columns not read from the field
+// named after the column
+var userColumnFields = columnFields{
+	"title": {"name"},
+}
+
 // LIST FUNCTION
 
 func listUsers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
@@ -211,7 +181,7 @@ func listUsers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	filters := setUserFilters(d, ctx)
 
 	for {
-		listUserResponse, err := gql.ListUsers(ctx, conn.client, pageSize, endCursor, true, &filters)
+		listUserResponse, err := gql.ListUsers(ctx, conn.selectColumns(d, userColumnFields), pageSize, endCursor, true, &filters)
 		if err != nil {
 			plugin.Logger(ctx).Error("linear_user.listUsers", "api_error", err)
 			return nil, err
@@ -250,7 +220,7 @@ func getUser(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 		return nil, err
 	}
 
-	getUserResponse, err := gql.GetUser(ctx, conn.client, &id)
+	getUserResponse, err := gql.GetUser(ctx, conn.selectColumns(d, userColumnFields), &id)
 	if err != nil {
 		plugin.Logger(ctx).Error("linear_user.getUser", "api_error", err)
 		return nil, err
@@ -259,83 +229,22 @@ func getUser(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 	return getUserResponse.User, nil
 }
 
+// userQualFilters are the key columns pushed down to the filter of list calls
+var userQualFilters = qualFilters{
+	"created_at":   {field: "CreatedAt"},
+	"updated_at":   {field: "UpdatedAt"},
+	"name":         {field: "Name"},
+	"display_name": {field: "DisplayName"},
+	"email":        {field: "Email"},
+	"active":       {field: "Active"},
+	"admin":        {field: "Admin"},
+	"is_me":        {field: "IsMe"},
+}
+
 // Set the requested filter
 func setUserFilters(d *plugin.QueryData, ctx context.Context) gql.UserFilter {
 	var filter gql.UserFilter
-	if d.Quals["created_at"] != nil {
-		createdAt := &gql.DateComparator{}
-		for _, q := range d.Quals["created_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				createdAt.Eq = timestamp
-			case ">":
-				createdAt.Gt = timestamp
-			case ">=":
-				createdAt.Gte = timestamp
-			case "<":
-				createdAt.Lt = timestamp
-			case "<=":
-				createdAt.Lte = timestamp
-			}
-		}
-		filter.CreatedAt = createdAt
-	}
-	if d.Quals["updated_at"] != nil {
-		updatedAt := &gql.DateComparator{}
-		for _, q := range d.Quals["updated_at"].Quals {
-			timestamp := types.Time(q.Value.GetTimestampValue().AsTime())
-			switch q.Operator {
-			case "=":
-				updatedAt.Eq = timestamp
-			case ">":
-				updatedAt.Gt = timestamp
-			case ">=":
-				updatedAt.Gte = timestamp
-			case "<":
-				updatedAt.Lt = timestamp
-			case "<=":
-				updatedAt.Lte = timestamp
-			}
-		}
-		filter.UpdatedAt = updatedAt
-	}
-	if d.EqualsQuals["name"] != nil {
-		name := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("name")),
-		}
-		filter.Name = name
-	}
-	if d.EqualsQuals["display_name"] != nil {
-		displayName := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("display_name")),
-		}
-		filter.DisplayName = displayName
-	}
-	if d.EqualsQuals["email"] != nil {
-		email := &gql.StringComparator{
-			Eq: types.String(d.EqualsQualString("email")),
-		}
-		filter.Email = email
-	}
-	if d.EqualsQuals["active"] != nil {
-		active := &gql.BooleanComparator{
-			Eq: types.Bool(d.EqualsQuals["active"].GetBoolValue()),
-		}
-		filter.Active = active
-	}
-	if d.EqualsQuals["admin"] != nil {
-		admin := &gql.BooleanComparator{
-			Eq: types.Bool(d.EqualsQuals["admin"].GetBoolValue()),
-		}
-		filter.Admin = admin
-	}
-	if d.EqualsQuals["is_me"] != nil {
-		isMe := &gql.BooleanComparator{
-			Eq: types.Bool(d.EqualsQuals["is_me"].GetBoolValue()),
-		}
-		filter.IsMe = isMe
-	}
+	setFilters(d, &filter, userQualFilters)
 
 	return filter
 }
@@ -0,0 +1,349 @@
+package linear
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestListTables(t *testing.T) {
+	tests := []struct {
+		name       string
+		list       plugin.HydrateFunc
+		quals      []testQual
+		rows       int
+		operations []string
+	}{
+		{"linear_attachment", listAttachments, nil, 1, []string{"listAttachments"}},
+		{"linear_attachment by issue", listAttachments, []testQual{stringQual("issue_id", "issue-1")}, 1, []string{"listIssueAttachments"}},
+		{"linear_comment", listComments, nil, 2, []string{"listComments"}},
+		{"linear_comment by parent", listComments, []testQual{stringQual("parent_id", "comment-1")}, 1, []string{"listCommentChildren"}},
+		{"linear_integration", listIntegrations, nil, 2, []string{"listIntegrations"}},
+		{"linear_integration by service", listIntegrations, []testQual{stringQual("service", "github")}, 1, []string{"listIntegrations"}},
+		{"linear_issue", listIssues, nil, 3, []string{"listIssues", "listTeamsByIds", "listUsersByIds", "listIssues"}},
+		{"linear_issue_label", listIssueLabels, nil, 2, []string{"listIssueLabels", "getIssueIds", "getIssueIds"}},
+		{"linear_organization", getOrganization, nil, 1, []string{"getOrganization"}},
+		{"linear_project", listProjects, nil, 1, []string{"listProjects"}},
+		{"linear_project by team", listProjects, []testQual{stringQual("team_id", "team-1")}, 1, []string{"listTeamProjects"}},
+		{"linear_team", listTeams, nil, 1, []string{"listTeams"}},
+		{"linear_team_membership", listTeamMemberships, nil, 1, []string{"listTeamMemberships"}},
+		{"linear_team_membership by team", listTeamMemberships, []testQual{stringQual("team_id", "team-1")}, 1, []string{"listTeamMembershipsByTeam"}},
+		{"linear_team_membership by user", listTeamMemberships, []testQual{stringQual("user_id", "user-1")}, 1, []string{"listTeamMembershipsByUser"}},
+		{"linear_user", listUsers, nil, 2, []string{"listUsers"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			q := newTestQuery(m, linearConfig{}, test.quals...)
+
+			if _, err := test.list(testContext(), q.QueryData, nil); err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			if len(q.rows) != test.rows {
+				t.Errorf("got %d rows, want %d", len(q.rows), test.rows)
+			}
+			if ops := m.Operations(); !reflect.DeepEqual(ops, test.operations) {
+				t.Errorf("got operations %v, want %v", ops, test.operations)
+			}
+			for _, req := range m.Requests() {
+				if req.Authorization != "lin_api_test" {
+					t.Errorf("got Authorization %q, want the personal API key", req.Authorization)
+				}
+			}
+		})
+	}
+}
+
+func TestGetTables(t *testing.T) {
+	tests := []struct {
+		name      string
+		get       plugin.HydrateFunc
+		operation string
+		variable  string
+	}{
+		{"linear_attachment", getAttachment, "getAttachment", "attachmentId"},
+		{"linear_comment", getComment, "getComment", "commentId"},
+		{"linear_integration", getIntegration, "getIntegration", "integrationId"},
+		{"linear_issue", getIssue, "getIssue", "issueId"},
+		{"linear_issue_label", getIssueLabel, "getIssueLabel", "issueLabelId"},
+		{"linear_project", getProject, "getProject", "projectId"},
+		{"linear_team", getTeam, "getTeam", "teamId"},
+		{"linear_team_membership", getTeamMembership, "getTeamMembership", "teamMembershipId"},
+		{"linear_user", getUser, "getUser", "userId"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			q := newTestQuery(m, linearConfig{}, stringQual("id", "some-id"))
+
+			item, err := test.get(testContext(), q.QueryData, nil)
+			if err != nil {
+				t.Fatalf("get failed: %v", err)
+			}
+			if reflect.ValueOf(item).IsNil() {
+				t.Fatal("got no item")
+			}
+			requests := m.Requests()
+			if len(requests) == 0 || requests[0].OperationName != test.operation {
+				t.Fatalf("got operations %v, want %s first", m.Operations(), test.operation)
+			}
+			if got := requests[0].Variables[test.variable]; got != "some-id" {
+				t.Errorf("got %s = %v, want some-id", test.variable, got)
+			}
+		})
+	}
+}
+
+func TestGetWithoutIdSkipsRequest(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{})
+
+	item, err := getIssue(testContext(), q.QueryData, nil)
+	if err != nil || item != nil {
+		t.Fatalf("got %v, %v, want nil, nil", item, err)
+	}
+	if ops := m.Operations(); len(ops) != 0 {
+		t.Errorf("got operations %v, want none", ops)
+	}
+}
+
+func TestListIssuesPagination(t *testing.T) {
+	m := newMockLinear(t)
+	pageSize := int64(2)
+	q := newTestQuery(m, linearConfig{PageSize: &pageSize})
+
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests []graphqlRequest
+	for _, req := range m.Requests() {
+		if req.OperationName == "listIssues" {
+			requests = append(requests, req)
+		}
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	for i, after := range []string{"", "cursor-1"} {
+		if got, _ := requests[i].Variables["after"].(string); got != after {
+			t.Errorf("request %d: got after %q, want %q", i, got, after)
+		}
+		if got := requests[i].Variables["first"]; got != float64(2) {
+			t.Errorf("request %d: got first %v, want 2", i, got)
+		}
+	}
+	values := columnValues(t, tableLinearIssue(testContext()), q.rows,
+		"id", "title", "identifier", "created_at", "sla_breaches_at", "team", "team_key", "assignee", "assignee_name", "assignee_email", "creator_id")
+	assertJSON(t, values, `[
+		{"id": "issue-1", "title": "First issue", "identifier": "ENG-1", "created_at": "2024-01-01T00:00:00Z",
+		 "team": {"id": "team-1", "key": "ENG", "name": "Engineering"}, "team_key": "ENG",
+		 "assignee": {"id": "user-1", "name": "Ada Lovelace", "email": "ada@example.com"},
+		 "assignee_name": "Ada Lovelace", "assignee_email": "ada@example.com", "creator_id": "user-1"},
+		{"id": "issue-2", "title": "Second issue", "identifier": "ENG-2", "created_at": "2024-01-02T00:00:00Z",
+		 "sla_breaches_at": "2024-01-03T00:00:00Z",
+		 "team": {"id": "team-1", "key": "ENG", "name": "Engineering"}, "team_key": "ENG"},
+		{"id": "issue-3", "title": "Third issue", "identifier": "ENG-3", "created_at": "2024-01-03T00:00:00Z",
+		 "team": {"id": "team-1", "key": "ENG", "name": "Engineering"}, "team_key": "ENG", "creator_id": "user-1"}
+	]`)
+}
+
+func TestListIssuesLoadsRelationsOncePerId(t *testing.T) {
+	m := newMockLinear(t)
+	pageSize := int64(2)
+	q := newTestQuery(m, linearConfig{PageSize: &pageSize})
+
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	// team-1 and user-1 are on both pages, and fetched once
+	want := []string{"listIssues", "listTeamsByIds", "listUsersByIds", "listIssues"}
+	if ops := m.Operations(); !reflect.DeepEqual(ops, want) {
+		t.Errorf("got operations %v, want %v", ops, want)
+	}
+	for _, req := range m.Requests() {
+		if req.OperationName == "listUsersByIds" {
+			filter, _ := json.Marshal(req.Variables["filter"])
+			if string(filter) != `{"id":{"in":["user-1"]}}` {
+				t.Errorf("got users filter %s, want user-1 once", filter)
+			}
+		}
+	}
+
+	// relations without selected columns are not loaded
+	m = newMockLinear(t)
+	q = newTestQuery(m, linearConfig{})
+	q.QueryContext.Columns = []string{"id", "title", "team_id"}
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ops := m.Operations(); !reflect.DeepEqual(ops, []string{"listIssues", "listIssues"}) {
+		t.Errorf("got operations %v, want only listIssues", ops)
+	}
+	assertJSON(t, columnValues(t, tableLinearIssue(testContext()), q.rows[:1], "team_id"), `[{"team_id": "team-1"}]`)
+}
+
+func TestListPageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize *int64
+		limit    *int64
+		want     float64
+	}{
+		{"default", nil, nil, 50},
+		{"configured", ptr(int64(100)), nil, 100},
+		{"capped", ptr(int64(500)), nil, 250},
+		{"limit", nil, ptr(int64(5)), 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockLinear(t)
+			q := newLimitedTestQuery(m, linearConfig{PageSize: test.pageSize}, test.limit)
+
+			if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got := m.Requests()[0].Variables["first"]; got != test.want {
+				t.Errorf("got first %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestListIssueLabelsFetchesRemainingIssueIds(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{})
+
+	if _, err := listIssueLabels(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, q.rows, `[
+		{"id": "label-1", "name": "Bug", "issues": {"pageInfo": {"hasNextPage": true, "endCursor": "cursor-1"},
+		 "nodes": [{"id": "issue-1"}, {"id": "issue-2"}, {"id": "issue-3"}]}},
+		{"id": "label-2", "name": "Feature", "issues": {"pageInfo": {"hasNextPage": false}, "nodes": []}}
+	]`)
+}
+
+func TestProjectTeamsAndMembersHydrate(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{})
+	if _, err := listProjects(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	h := &plugin.HydrateData{Item: q.rows[0]}
+
+	teams, err := getProjectTeams(testContext(), q.QueryData, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, teams, `[{"id": "team-1", "key": "ENG", "name": "Engineering"}, {"id": "team-2", "key": "OPS", "name": "Operations"}]`)
+
+	members, err := getProjectMembers(testContext(), q.QueryData, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, members, `[{"id": "user-1", "name": "Ada Lovelace", "email": "ada@example.com"}]`)
+
+	if got := m.Requests()[1].Variables["projectId"]; got != "project-1" {
+		t.Errorf("got projectId %v, want project-1", got)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestListIssuesSharded(t *testing.T) {
+	m := newMockLinear(t)
+	shards := int64(3)
+	q := newTestQuery(m, linearConfig{IssueScanShards: &shards}, stringQual("title", "Done"))
+
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	// each shard pages through the two fixture pages
+	if len(q.rows) != 9 {
+		t.Errorf("got %d rows, want 9", len(q.rows))
+	}
+
+	requests := m.Requests()
+	if requests[0].OperationName != "getIssueCreatedAtRange" {
+		t.Fatalf("got operations %v, want the creation date range first", m.Operations())
+	}
+	var shardFilters []string
+	loads := map[string]int{}
+	for _, req := range requests[1:] {
+		if req.OperationName != "listIssues" {
+			loads[req.OperationName]++
+			continue
+		}
+		if req.Variables["after"] != nil && req.Variables["after"] != "" {
+			continue
+		}
+		filter, _ := json.Marshal(req.Variables["filter"])
+		shardFilters = append(shardFilters, string(filter))
+	}
+	sort.Strings(shardFilters)
+	want := []string{
+		`{"and":[{"title":{"eq":"Done"}},{"createdAt":{"gte":"2024-01-01T00:00:00Z","lt":"2024-01-02T00:00:00Z"}}]}`,
+		`{"and":[{"title":{"eq":"Done"}},{"createdAt":{"gte":"2024-01-02T00:00:00Z","lt":"2024-01-03T00:00:00Z"}}]}`,
+		`{"and":[{"title":{"eq":"Done"}},{"createdAt":{"gte":"2024-01-03T00:00:00Z","lte":"2024-01-04T00:00:00Z"}}]}`,
+	}
+	if !reflect.DeepEqual(shardFilters, want) {
+		t.Errorf("got shard filters\n%s\nwant\n%s", strings.Join(shardFilters, "\n"), strings.Join(want, "\n"))
+	}
+	// the shards share the related entities of the query
+	if want := map[string]int{"listTeamsByIds": 1, "listUsersByIds": 1}; !reflect.DeepEqual(loads, want) {
+		t.Errorf("got related entity requests %v, want %v", loads, want)
+	}
+}
+
+func TestListIssuesShardingSkippedWithLimit(t *testing.T) {
+	m := newMockLinear(t)
+	shards := int64(3)
+	q := newLimitedTestQuery(m, linearConfig{IssueScanShards: &shards}, ptr(int64(10)))
+
+	if _, err := listIssues(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ops := m.Operations(); !reflect.DeepEqual(ops, []string{"listIssues", "listTeamsByIds", "listUsersByIds", "listIssues"}) {
+		t.Errorf("got operations %v, want a sequential scan", ops)
+	}
+}
+
+func TestListIssueLabelsIssueIds(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{})
+
+	if _, err := listIssueLabels(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	values := columnValues(t, tableLinearIssueLabel(testContext()), q.rows, "id", "issue_ids")
+	assertJSON(t, values, `[
+		{"id": "label-1", "issue_ids": [{"id": "issue-1"}, {"id": "issue-2"}, {"id": "issue-3"}]},
+		{"id": "label-2", "issue_ids": []}
+	]`)
+	for _, req := range m.Requests() {
+		if req.OperationName == "getIssueIds" && req.Variables["first"] != float64(issueIdsPageSize) {
+			t.Errorf("got getIssueIds first %v, want %d", req.Variables["first"], issueIdsPageSize)
+		}
+	}
+
+	// issue ids are not fetched unless selected
+	m = newMockLinear(t)
+	q = newTestQuery(m, linearConfig{})
+	q.QueryContext.Columns = []string{"id", "name"}
+	if _, err := listIssueLabels(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ops := m.Operations(); !reflect.DeepEqual(ops, []string{"listIssueLabels"}) {
+		t.Errorf("got operations %v, want only listIssueLabels", ops)
+	}
+}
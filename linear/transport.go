@@ -0,0 +1,141 @@
+package linear
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// defaultRequestTimeout bounds each attempt of a request when no
+	// request_timeout is configured
+	defaultRequestTimeout = 60 * time.Second
+	// retryBackoff is the wait before the first retry, doubled for each
+	// following one
+	retryBackoff = 500 * time.Millisecond
+)
+
+// newBaseTransport returns the transport sending requests to the API, with
+// the proxy and CA bundle of the connection config.
+func newBaseTransport(config linearConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.HTTPProxy != nil && *config.HTTPProxy != "" {
+		proxy, err := url.Parse(*config.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http_proxy: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.CABundle != nil && *config.CABundle != "" {
+		bundle, err := os.ReadFile(*config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_bundle: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("ca_bundle %s contains no PEM certificates", *config.CABundle)
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	return transport, nil
+}
+
+// requestTimeout returns the timeout of each attempt of a request.
+func requestTimeout(config linearConfig) time.Duration {
+	if config.RequestTimeout != nil {
+		return time.Duration(*config.RequestTimeout) * time.Second
+	}
+	return defaultRequestTimeout
+}
+
+// retryTransport bounds each attempt of a request by a timeout, and retries
+// attempts which time out, fail to connect or hit a server error.
+type retryTransport struct {
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	wrapped    http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			if req.GetBody == nil {
+				return nil, errors.New("cannot retry a request without GetBody")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.roundTripAttempt(attemptReq)
+		if attempt >= t.maxRetries || req.Context().Err() != nil || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleepContext(req.Context(), t.backoff<<attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTripAttempt sends a single attempt, cancelled once the timeout passes
+// or the response body is closed.
+func (t *retryTransport) roundTripAttempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.wrapped.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.wrapped.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryable reports whether an attempt failed in a way worth retrying.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,225 @@
+package linear
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newRetryRequest(t *testing.T) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://linear.test/graphql", strings.NewReader(`{"query":"{}"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestRetryTransportRetriesServerErrors(t *testing.T) {
+	var bodies []string
+	statuses := []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}
+	transport := &retryTransport{
+		maxRetries: 2,
+		backoff:    time.Millisecond,
+		wrapped: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			status := statuses[len(bodies)-1]
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+
+	resp, err := transport.RoundTrip(newRetryRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want 200", resp.StatusCode)
+	}
+	for i, body := range bodies {
+		if body != `{"query":"{}"}` {
+			t.Errorf("attempt %d sent body %q", i, body)
+		}
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	attempts := 0
+	transport := &retryTransport{
+		maxRetries: 2,
+		backoff:    time.Millisecond,
+		wrapped: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection refused")
+		}),
+	}
+
+	if _, err := transport.RoundTrip(newRetryRequest(t)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 3 {
+		t.Errorf("got %d attempts, want 3", attempts)
+	}
+
+	// client errors are not retried
+	attempts = 0
+	transport.wrapped = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	if _, err := transport.RoundTrip(newRetryRequest(t)); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts for a 400, want 1", attempts)
+	}
+}
+
+func TestRetryTransportTimesOutAttempts(t *testing.T) {
+	attempts := 0
+	transport := &retryTransport{
+		timeout:    10 * time.Millisecond,
+		maxRetries: 1,
+		backoff:    time.Millisecond,
+		wrapped: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				// hang until the attempt times out
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+
+	resp, err := transport.RoundTrip(newRetryRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want the hung one retried", attempts)
+	}
+
+	// the caller cancelling the request is not retried
+	attempts = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := transport.RoundTrip(newRetryRequest(t).WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts after cancellation, want 1", attempts)
+	}
+}
+
+// newTestProxy starts a forwarding proxy, and returns it with the URLs of the
+// requests it forwarded.
+func newTestProxy(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.URL.String())
+		mu.Unlock()
+		r.RequestURI = ""
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for key, values := range resp.Header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), proxied...)
+	}
+}
+
+func TestHTTPProxy(t *testing.T) {
+	m := newMockLinear(t)
+	proxy, proxied := newTestProxy(t)
+
+	q := newTestQuery(m, linearConfig{HTTPProxy: &proxy.URL})
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := proxied(); len(got) != 1 || got[0] != m.server.URL+"/" {
+		t.Errorf("got proxied requests %v, want one to %s", got, m.server.URL)
+	}
+}
+
+func TestHTTPProxyForOAuthTokens(t *testing.T) {
+	m := newMockLinear(t)
+	m.Authorize("Bearer access-1")
+	o := newMockOAuth(t)
+	proxy, proxied := newTestProxy(t)
+
+	config := o.config("")
+	config.HTTPProxy = &proxy.URL
+	q := newTestQuery(m, config)
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{o.server.URL + "/", m.server.URL + "/"}
+	if got := proxied(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got proxied requests %v, want the token request then the query %v", got, want)
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	m := newMockLinear(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(m.serveHTTP))
+	// the handshake failing without ca_bundle is expected
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// the self-signed certificate of the server is not trusted by default
+	q := newTestQuery(m, linearConfig{Endpoint: &server.URL})
+	if _, err := listTeams(testContext(), q.QueryData, nil); err == nil {
+		t.Fatal("expected a certificate error without ca_bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certificate, 0600); err != nil {
+		t.Fatal(err)
+	}
+	q = newTestQuery(m, linearConfig{Endpoint: &server.URL, CABundle: &bundle})
+	if _, err := listTeams(testContext(), q.QueryData, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	q = newTestQuery(m, linearConfig{Endpoint: &server.URL, CABundle: &bundle})
+	if _, err := listTeams(testContext(), q.QueryData, nil); err == nil || !strings.Contains(err.Error(), "ca_bundle") {
+		t.Errorf("got %v, want an invalid ca_bundle error", err)
+	}
+}
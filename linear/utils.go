@@ -2,34 +2,63 @@ package linear
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/Khan/genqlient/graphql"
+	"github.com/turbot/steampipe-plugin-sdk/v5/memoize"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
 type authedTransport struct {
-	key     string
-	wrapped http.RoundTripper
+	credentials credentials
+	wrapped     http.RoundTripper
 }
 
+// defaultEndpoint is the Linear GraphQL API used when no endpoint is configured
+const defaultEndpoint = "https://api.linear.app/graphql"
+
 type linearClient struct {
 	client   graphql.Client
 	pageSize int64
+	// issueScanShards is the number of creation date ranges full issue scans
+	// are split into, and paged through concurrently
+	issueScanShards int
+	// cache persists full scans on disk, nil unless response_cache_dir is set
+	cache *responseCache
+	// watermarks persists the progress of the changes tables, nil unless
+	// sync_state_dir is set
+	watermarks *watermarkStore
 }
 
+// RoundTrip sends the request with the connection's credentials, and retries
+// it once with new credentials if the API rejects them.
 func (t *authedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorization, err := t.credentials.authorization(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	req.Header.Set("Authorization", authorization)
+	resp, err := t.wrapped.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || req.GetBody == nil || !t.credentials.invalidate(authorization) {
+		return resp, err
+	}
 
-	// check if it is a personal access key
-	if strings.HasPrefix(t.key, "lin_api") {
-		req.Header.Set("Authorization", t.key)
-	} else {
-		req.Header.Set("Authorization", "Bearer " + t.key)
+	retryAuthorization, retryErr := t.credentials.authorization(req.Context())
+	if retryErr != nil {
+		return nil, retryErr
+	}
+	if retryAuthorization == authorization {
+		return resp, nil
+	}
+	if retry.Body, err = req.GetBody(); err != nil {
+		return nil, err
 	}
-	return t.wrapped.RoundTrip(req)
+	resp.Body.Close()
+	retry.Header.Set("Authorization", retryAuthorization)
+	return t.wrapped.RoundTrip(retry)
 }
 
 func connect(ctx context.Context, d *plugin.QueryData) (*linearClient, error) {
@@ -41,17 +70,38 @@ func connect(ctx context.Context, d *plugin.QueryData) (*linearClient, error) {
 	return conn.(*linearClient), nil
 }
 
-var connectCached = plugin.HydrateFunc(connectUncached).Memoize()
+var connectCached = plugin.HydrateFunc(connectUncached).Memoize(memoize.WithCacheKeyFunction(connectCacheKey))
+
+// connectCacheKey caches a client per workspace of the connection.
+func connectCacheKey(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	return "connect-" + workspaceAlias(ctx), nil
+}
 
 func connectUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (any, error) {
-	token := os.Getenv("LINEAR_TOKEN")
+	endpoint := os.Getenv("LINEAR_API_URL")
 
 	// default Size
 	pageSize := int64(50)
 
 	linearConfig := GetConfig(d.Connection)
-	if linearConfig.Token != nil {
-		token = *linearConfig.Token
+	if err := validateConfig(linearConfig); err != nil {
+		return nil, err
+	}
+	if alias := workspaceAlias(ctx); alias != "" {
+		// the workspaces of a connection only differ by their token
+		token, ok := linearConfig.Workspaces[alias]
+		if !ok {
+			return nil, fmt.Errorf("workspace %q is not configured in the connection", alias)
+		}
+		linearConfig.Token = &token
+	}
+	stateKey := workspaceStateKey(ctx, d.Connection.Name)
+	logDeprecatedFields(ctx, d.Connection.Name)
+	if linearConfig.Endpoint != nil {
+		endpoint = *linearConfig.Endpoint
+	}
+	if endpoint == "" {
+		endpoint = defaultEndpoint
 	}
 	if linearConfig.PageSize != nil {
 		// check if the provided value is more than the max limit
@@ -62,21 +112,59 @@ func connectUncached(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		}
 	}
 
-	if token == "" {
-		return nil, errors.New("'token' must be set in the connection configuration. Edit your connection configuration file and then restart Steampipe")
+	credentials, err := getCredentials(stateKey, linearConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	baseTransport, err := newBaseTransport(linearConfig)
+	if err != nil {
+		return nil, err
+	}
+	maxRetries := 0
+	if linearConfig.MaxRetries != nil {
+		maxRetries = int(*linearConfig.MaxRetries)
+	}
+
+	// requests go through the connection's rate limits and page sizes, omit
+	// the fields removed from the API, and errors are returned with their
+	// GraphQL error codes
+	var transport http.RoundTripper = &retryTransport{
+		timeout:    requestTimeout(linearConfig),
+		maxRetries: maxRetries,
+		backoff:    retryBackoff,
+		wrapped:    baseTransport,
+	}
+	transport = &rateLimitTransport{
+		limiter: getRateLimiter(stateKey),
+		wrapped: transport,
 	}
+	transport = &pageSizeTransport{
+		sizer:   getPageSizer(stateKey),
+		wrapped: transport,
+	}
+	transport = &removedFieldTransport{
+		fields:  getRemovedFields(stateKey),
+		wrapped: transport,
+	}
+	transport = &failedResponseTransport{wrapped: transport}
 
 	httpClient := http.Client{
 		Transport: &authedTransport{
-			key:     token,
-			wrapped: http.DefaultTransport,
+			credentials: credentials,
+			wrapped:     transport,
 		},
 	}
-	graphqlClient := graphql.NewClient("https://api.linear.app/graphql", &httpClient)
+	graphqlClient := &apiErrorClient{wrapped: graphql.NewClient(endpoint, &httpClient)}
 
 	gqlClient := &linearClient{
-		client:   graphqlClient,
-		pageSize: pageSize,
+		client:     graphqlClient,
+		pageSize:   pageSize,
+		cache:      newResponseCache(stateKey, linearConfig),
+		watermarks: getWatermarkStore(stateKey, linearConfig),
+	}
+	if linearConfig.IssueScanShards != nil {
+		gqlClient.issueScanShards = int(*linearConfig.IssueScanShards)
 	}
 
 	return gqlClient, nil
@@ -0,0 +1,126 @@
+package linear
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// watermarksFile holds the watermarks of a connection, by table and consumer
+	watermarksFile = "watermarks.json"
+	// watermarkOverlap is subtracted from a watermark when asking for the
+	// entities changed since, so entities committed late are not missed
+	watermarkOverlap = time.Minute
+)
+
+// watermarkStore persists the updated_at high-water mark of the changes tables
+// of a connection by consumer, so each scan only returns the entities changed
+// since the consumer last acknowledged a complete one.
+type watermarkStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// watermark is the progress of a consumer of a changes table.
+type watermark struct {
+	// Committed is the high-water mark acknowledged by the consumer
+	Committed *time.Time `json:"committed,omitempty"`
+	// Pending is the high-water mark of the latest complete scan, committed
+	// when the consumer acknowledges it
+	Pending *time.Time `json:"pending,omitempty"`
+}
+
+var watermarkStores sync.Map
+
+// getWatermarkStore returns the watermarks of the connection, or nil when no
+// sync_state_dir is configured.
+func getWatermarkStore(connectionName string, config linearConfig) *watermarkStore {
+	if config.SyncStateDir == nil || *config.SyncStateDir == "" {
+		return nil
+	}
+	dir := filepath.Join(*config.SyncStateDir, unsafePathCharacters.ReplaceAllString(connectionName, "_"))
+	store, _ := watermarkStores.LoadOrStore(dir, &watermarkStore{dir: dir})
+	return store.(*watermarkStore)
+}
+
+// load returns the committed watermark of the consumer, or nil before it
+// acknowledges its first complete scan.
+func (s *watermarkStore) load(key string) (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watermarks, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return watermarks[key].Committed, nil
+}
+
+// stage records the high-water mark of a complete scan of the consumer, to be
+// committed when it is acknowledged.
+func (s *watermarkStore) stage(key string, pending time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watermarks, err := s.read()
+	if err != nil {
+		return err
+	}
+	current := watermarks[key]
+	if current.Committed != nil && !pending.After(*current.Committed) {
+		return nil
+	}
+	current.Pending = &pending
+	watermarks[key] = current
+	return s.write(watermarks)
+}
+
+// acknowledge commits the pending watermark of the consumer, returning the
+// committed watermark.
+func (s *watermarkStore) acknowledge(key string) (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watermarks, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	current := watermarks[key]
+	if current.Pending == nil {
+		return current.Committed, nil
+	}
+	if current.Committed == nil || current.Pending.After(*current.Committed) {
+		current.Committed = current.Pending
+	}
+	current.Pending = nil
+	watermarks[key] = current
+	return current.Committed, s.write(watermarks)
+}
+
+func (s *watermarkStore) read() (map[string]watermark, error) {
+	watermarks := map[string]watermark{}
+	data, err := os.ReadFile(filepath.Join(s.dir, watermarksFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return watermarks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &watermarks); err != nil {
+		return nil, err
+	}
+	return watermarks, nil
+}
+
+func (s *watermarkStore) write(watermarks map[string]watermark) error {
+	data, err := json.MarshalIndent(watermarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.dir, watermarksFile, data)
+}
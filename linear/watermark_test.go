@@ -0,0 +1,143 @@
+package linear
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListIssueChanges(t *testing.T) {
+	dir := t.TempDir()
+	m := newMockLinear(t)
+	config := linearConfig{SyncStateDir: &dir}
+	scanChanges := func(consumer string, quals ...testQual) ([]map[string]interface{}, string) {
+		before := len(m.Requests())
+		q := newTestQuery(m, config, append(quals, stringQual("consumer", consumer))...)
+		if _, err := listIssueChanges(testContext(), q.QueryData, nil); err != nil {
+			t.Fatal(err)
+		}
+		filter, _ := json.Marshal(m.Requests()[before].Variables["filter"])
+		return columnValues(t, tableLinearIssueChanges(testContext()), q.rows, "id", "change_type"), string(filter)
+	}
+	acknowledge := func(consumer string) {
+		q := newTestQuery(m, config, stringQual("consumer", consumer), boolQual("acknowledge", true))
+		if _, err := listIssueChanges(testContext(), q.QueryData, nil); err != nil {
+			t.Fatal(err)
+		}
+		if len(q.rows) != 0 {
+			t.Errorf("got %d rows, want none from an acknowledgement", len(q.rows))
+		}
+	}
+	watermarks := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_"), watermarksFile)
+	readWatermark := func(consumer string) watermark {
+		data, _ := os.ReadFile(watermarks)
+		var values map[string]watermark
+		_ = json.Unmarshal(data, &values)
+		return values["linear_issue_changes/"+consumer]
+	}
+	setWatermark := func(consumer string, committed string) {
+		if err := os.WriteFile(watermarks, []byte(`{"linear_issue_changes/`+consumer+`": {"committed": "`+committed+`"}}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first scan returns every issue, and stages the latest update
+	// without committing it
+	rows, filter := scanChanges("warehouse")
+	if strings.Contains(filter, "updatedAt") {
+		t.Errorf("got filter %s, want every issue", filter)
+	}
+	assertJSON(t, rows, `[
+		{"id": "issue-1", "change_type": "created"},
+		{"id": "issue-2", "change_type": "created"},
+		{"id": "issue-3", "change_type": "created"}
+	]`)
+	if got := readWatermark("warehouse"); got.Committed != nil || got.Pending == nil || got.Pending.Format(time.RFC3339) != "2024-01-06T00:00:00Z" {
+		t.Errorf("got watermark %+v, want the latest updatedAt pending", got)
+	}
+
+	// until it is acknowledged, the same changes are returned again
+	rows, _ = scanChanges("warehouse")
+	if len(rows) != 3 {
+		t.Errorf("got %d rows, want every issue again before an acknowledgement", len(rows))
+	}
+	acknowledge("warehouse")
+	if got := readWatermark("warehouse"); got.Pending != nil || got.Committed == nil || got.Committed.Format(time.RFC3339) != "2024-01-06T00:00:00Z" {
+		t.Errorf("got watermark %+v, want the latest updatedAt committed", got)
+	}
+
+	// later scans only ask for the issues updated since the watermark
+	setWatermark("warehouse", "2024-01-03T12:00:00Z")
+	rows, filter = scanChanges("warehouse")
+	if !strings.Contains(filter, `{"updatedAt":{"gt":"2024-01-03T11:59:00Z"}}`) {
+		t.Errorf("got filter %s, want issues updated since the watermark", filter)
+	}
+	assertJSON(t, rows, `[
+		{"id": "issue-1", "change_type": "updated"},
+		{"id": "issue-2", "change_type": "updated"},
+		{"id": "issue-3", "change_type": "archived"}
+	]`)
+
+	// each consumer has its own watermark
+	rows, filter = scanChanges("audit")
+	if strings.Contains(filter, "updatedAt") || len(rows) != 3 {
+		t.Errorf("got filter %s and %d rows, want every issue for a new consumer", filter, len(rows))
+	}
+
+	// scans restricted by quals do not stage a watermark
+	setWatermark("warehouse", "2024-01-03T12:00:00Z")
+	scanChanges("warehouse", stringQual("title", "First issue"))
+	acknowledge("warehouse")
+	if got := readWatermark("warehouse"); got.Committed == nil || got.Committed.Format(time.RFC3339) != "2024-01-03T12:00:00Z" {
+		t.Errorf("got watermark %+v, want it unchanged", got)
+	}
+}
+
+func TestListIssueChangesBypassesQueryCache(t *testing.T) {
+	dir := t.TempDir()
+	m := newMockLinear(t)
+	server := newPluginServer(t, fmt.Sprintf("token = %q\nendpoint = %q\nsync_state_dir = %q\n", "lin_api_test", m.server.URL, dir))
+
+	// the same query twice in a row reaches the API, and stages, both times
+	for _, callId := range []string{"first", "second"} {
+		before := len(m.Operations())
+		rows := executeQuery(t, server, callId, "linear_issue_changes", []string{"id", "consumer"}, map[string]string{"consumer": "warehouse"})
+		if len(rows) != 3 {
+			t.Errorf("%s query: got %d rows, want 3", callId, len(rows))
+		}
+		if len(m.Operations()) == before {
+			t.Errorf("%s query: got no API requests, want the query cache bypassed", callId)
+		}
+	}
+}
+
+func TestListIssueChangesRequiresSyncStateDir(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, linearConfig{})
+	if _, err := listIssueChanges(testContext(), q.QueryData, nil); err == nil || !strings.Contains(err.Error(), "sync_state_dir") {
+		t.Errorf("got %v, want a sync_state_dir error", err)
+	}
+}
+
+func TestWatermarkOnlyAdvances(t *testing.T) {
+	store := &watermarkStore{dir: t.TempDir()}
+	later := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	earlier := later.Add(-time.Hour)
+
+	for _, watermark := range []time.Time{later, earlier} {
+		if err := store.stage("linear_issue_changes/warehouse", watermark); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.acknowledge("linear_issue_changes/warehouse"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := store.load("linear_issue_changes/warehouse")
+	if err != nil || got == nil || !got.Equal(later) {
+		t.Errorf("got %v, %v, want %v", got, err, later)
+	}
+}
@@ -0,0 +1,98 @@
+package linear
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+)
+
+const (
+	// matrixKeyWorkspace is the matrix key of the alias of a workspace
+	matrixKeyWorkspace = "workspace"
+	// matrixKeyOrganizationId is the matrix key of the organization of a
+	// workspace, so organization_id quals prune the workspaces queried
+	matrixKeyOrganizationId = "organization_id"
+)
+
+// workspaceMatrix returns a matrix item for each workspace of the connection,
+// so that every list and get call is made once per workspace. Connections
+// with a single token have a single item without a workspace. Each item holds
+// the organization of its workspace, so the SDK leaves out the items not
+// matching the organization_id quals of a query.
+func workspaceMatrix(ctx context.Context, d *plugin.QueryData) []map[string]interface{} {
+	config := GetConfig(d.Connection)
+	if len(config.Workspaces) == 0 {
+		return []map[string]interface{}{workspaceMatrixItem(ctx, d, "")}
+	}
+
+	var aliases []string
+	for alias := range config.Workspaces {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	// the organization of each workspace is looked up concurrently
+	matrix := make([]map[string]interface{}, len(aliases))
+	var wg sync.WaitGroup
+	for i, alias := range aliases {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matrix[i] = workspaceMatrixItem(ctx, d, alias)
+		}()
+	}
+	wg.Wait()
+
+	return matrix
+}
+
+// workspaceMatrixItem returns the matrix item of the workspace, or of the
+// connection when alias is "". A failed organization lookup is left to fail
+// the calls of the workspace.
+func workspaceMatrixItem(ctx context.Context, d *plugin.QueryData, alias string) map[string]interface{} {
+	item := map[string]interface{}{matrixKeyOrganizationId: ""}
+	workspaceCtx := context.WithValue(ctx, context_key.MatrixItem, map[string]interface{}{})
+	if alias != "" {
+		item[matrixKeyWorkspace] = alias
+		workspaceCtx = context.WithValue(ctx, context_key.MatrixItem, map[string]interface{}{matrixKeyWorkspace: alias})
+	}
+	organizationId, err := getOrganizationIdMemoized(workspaceCtx, d, nil)
+	if err != nil {
+		plugin.Logger(ctx).Error("workspaceMatrix", "workspace", alias, "api_error", err)
+		return item
+	}
+	item[matrixKeyOrganizationId] = organizationId
+	return item
+}
+
+// workspaceAlias returns the alias of the workspace the call is made for, or
+// "" for connections with a single token.
+func workspaceAlias(ctx context.Context) string {
+	alias, _ := plugin.GetMatrixItem(ctx)[matrixKeyWorkspace].(string)
+	return alias
+}
+
+// workspaceStateKey identifies the state kept for the workspace of the call,
+// such as its rate limits and page sizes, among all connections.
+func workspaceStateKey(ctx context.Context, connectionName string) string {
+	if alias := workspaceAlias(ctx); alias != "" {
+		return connectionName + "/" + alias
+	}
+	return connectionName
+}
+
+// hasQuals reports whether the call has quals other than those of its
+// matrix item and the ignored columns.
+func hasQuals(ctx context.Context, d *plugin.QueryData, ignored ...string) bool {
+	matrixItem := plugin.GetMatrixItem(ctx)
+	for column := range d.Quals {
+		if _, ok := matrixItem[column]; !ok && !slices.Contains(ignored, column) {
+			return true
+		}
+	}
+	return false
+}
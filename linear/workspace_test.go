@@ -0,0 +1,157 @@
+package linear
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+)
+
+// workspacesConfig aggregates two workspaces, whose requests are answered
+// from testdata and testdata/workspaces/initech.
+func workspacesConfig(m *mockLinear) linearConfig {
+	m.FixturesFor("lin_api_initech", "testdata/workspaces/initech")
+	return linearConfig{Workspaces: map[string]string{
+		"acme":    "lin_api_acme",
+		"initech": "lin_api_initech",
+	}}
+}
+
+func TestWorkspaceMatrix(t *testing.T) {
+	m := newMockLinear(t)
+	q := newTestQuery(m, workspacesConfig(m))
+
+	assertJSON(t, workspaceMatrix(testContext(), q.QueryData), `[
+		{"workspace": "acme", "organization_id": "org-1"},
+		{"workspace": "initech", "organization_id": "org-2"}
+	]`)
+}
+
+func TestWorkspaceMatrixWithoutWorkspaces(t *testing.T) {
+	m := newMockLinear(t)
+
+	// connections with a single token are queried once, for their organization
+	q := newTestQuery(m, linearConfig{})
+	assertJSON(t, workspaceMatrix(testContext(), q.QueryData), `[{"organization_id": "org-1"}]`)
+}
+
+func TestOrganizationIdPrunesWorkspaces(t *testing.T) {
+	m := newMockLinear(t)
+	m.FixturesFor("lin_api_initech", "testdata/workspaces/initech")
+	server := newPluginServer(t, fmt.Sprintf("endpoint = %q\nworkspaces = {\n  acme = \"lin_api_acme\"\n  initech = \"lin_api_initech\"\n}\n", m.server.URL))
+	columns := []string{"id", "workspace", "organization_id"}
+
+	// the workspaces of other organizations are left out, and the connection
+	// is not, although its workspaces belong to several organizations
+	for _, test := range []struct {
+		organizationId string
+		want           string
+	}{
+		{"org-1", `[{"id": "team-1", "workspace": "acme", "organization_id": "org-1"}]`},
+		{"org-2", `[{"id": "team-2", "workspace": "initech", "organization_id": "org-2"}]`},
+		{"org-3", `null`},
+	} {
+		rows := executeQuery(t, server, test.organizationId, "linear_team", columns, map[string]string{"organization_id": test.organizationId})
+		assertJSON(t, rows, test.want)
+	}
+
+	rows := executeQuery(t, server, "all", "linear_team", columns, nil)
+	sort.Slice(rows, func(i, j int) bool { return rows[i]["id"] < rows[j]["id"] })
+	assertJSON(t, rows, `[
+		{"id": "team-1", "workspace": "acme", "organization_id": "org-1"},
+		{"id": "team-2", "workspace": "initech", "organization_id": "org-2"}
+	]`)
+}
+
+func TestOrganizationIdPrunesConnection(t *testing.T) {
+	m := newMockLinear(t)
+	server := newPluginServer(t, fmt.Sprintf("token = %q\nendpoint = %q\n", "lin_api_test", m.server.URL))
+
+	if rows := executeQuery(t, server, "other", "linear_team", []string{"id"}, map[string]string{"organization_id": "org-2"}); len(rows) != 0 {
+		t.Errorf("got rows %v, want none for another organization", rows)
+	}
+	if ops := m.Operations(); slices.Contains(ops, "listTeams") {
+		t.Errorf("got operations %v, want the teams of another organization left unlisted", ops)
+	}
+	assertJSON(t, executeQuery(t, server, "same", "linear_team", []string{"id"}, map[string]string{"organization_id": "org-1"}), `[{"id": "team-1"}]`)
+}
+
+func TestWorkspacesRejectOtherCredentials(t *testing.T) {
+	m := newMockLinear(t)
+	tokenFile := "/run/secrets/linear"
+	config := workspacesConfig(m)
+	config.TokenFile = &tokenFile
+	q := newTestQuery(m, config)
+
+	ctx := context.WithValue(testContext(), context_key.MatrixItem, map[string]interface{}{matrixKeyWorkspace: "acme"})
+	_, err := listTeams(ctx, q.QueryData, nil)
+	if err == nil || !strings.Contains(err.Error(), "'workspaces' cannot be combined with") {
+		t.Errorf("got error %v, want workspaces and token_file rejected", err)
+	}
+	if ops := m.Operations(); len(ops) != 0 {
+		t.Errorf("got operations %v, want none", ops)
+	}
+}
+
+func TestListTeamsPerWorkspace(t *testing.T) {
+	m := newMockLinear(t)
+	config := workspacesConfig(m)
+
+	for _, test := range []struct {
+		workspace     string
+		authorization string
+		want          string
+	}{
+		{"acme", "lin_api_acme", `[{"id": "team-1", "key": "ENG", "name": "Engineering"}]`},
+		{"initech", "lin_api_initech", `[{"id": "team-2", "key": "OPS", "name": "Operations"}]`},
+	} {
+		before := len(m.Requests())
+		q := newTestQuery(m, config)
+		ctx := context.WithValue(testContext(), context_key.MatrixItem, map[string]interface{}{
+			matrixKeyWorkspace:      test.workspace,
+			matrixKeyOrganizationId: "org-" + test.workspace,
+		})
+		if _, err := listTeams(ctx, q.QueryData, nil); err != nil {
+			t.Fatal(err)
+		}
+		assertJSON(t, columnValues(t, tableLinearTeam(ctx), q.rows, "id", "key", "name"), test.want)
+		for _, req := range m.Requests()[before:] {
+			if req.Authorization != test.authorization {
+				t.Errorf("%s: got Authorization %q, want %q", test.workspace, req.Authorization, test.authorization)
+			}
+		}
+
+		// the organization of the workspace is read from its matrix item
+		organizationId, err := getOrganizationId(ctx, q.QueryData, nil)
+		if err != nil || organizationId != "org-"+test.workspace {
+			t.Errorf("%s: got organization %v, %v, want org-%s", test.workspace, organizationId, err, test.workspace)
+		}
+	}
+	if ops := m.Operations(); len(ops) != 2 {
+		t.Errorf("got operations %v, want a listTeams per workspace", ops)
+	}
+}
+
+func TestWorkspacesConfig(t *testing.T) {
+	file, diags := hclsyntax.ParseConfig([]byte(`
+workspaces = {
+  acme    = "lin_api_acme"
+  initech = "lin_api_initech"
+}
+`), "linear.spc", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	var config linearConfig
+	if diags := gohcl.DecodeBody(file.Body, nil, &config); diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	assertJSON(t, config.Workspaces, `{"acme": "lin_api_acme", "initech": "lin_api_initech"}`)
+}